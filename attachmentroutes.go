@@ -0,0 +1,53 @@
+package botbooter
+
+import (
+	"mime"
+	"strings"
+)
+
+type AttachmentFilter func(attachment Attachment) bool
+
+func ImageAttachments(attachment Attachment) bool {
+	return attachment.IsImage
+}
+
+// MimeType matches attachments by MIME type, either exactly ("application/pdf")
+// or by top-level type ("image/*").
+func MimeType(patterns ...string) AttachmentFilter {
+	return func(attachment Attachment) bool {
+		mimeType, _, err := mime.ParseMediaType(attachment.MimeType)
+		if err != nil {
+			return false
+		}
+
+		for _, pattern := range patterns {
+			pattern = strings.ToLower(pattern)
+			if pattern == "*/*" || pattern == mimeType {
+				return true
+			}
+			if strings.HasSuffix(pattern, "/*") && strings.HasPrefix(mimeType, strings.TrimSuffix(pattern, "*")) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (b *Bot) matchAttachments(message *Message, filter AttachmentFilter) []Attachment {
+	if message.SlackData == nil && (message.DiscordData == nil || message.DiscordData.Message == nil) {
+		return nil
+	}
+
+	attachments, err := b.GetAttachments(message)
+	if err != nil {
+		return nil
+	}
+
+	var matched []Attachment
+	for _, attachment := range attachments {
+		if filter(attachment) {
+			matched = append(matched, attachment)
+		}
+	}
+	return matched
+}
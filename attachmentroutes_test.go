@@ -0,0 +1,129 @@
+package botbooter
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/slack-go/slack/slackevents"
+)
+
+func TestMimeType(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		mimeType string
+		want     bool
+	}{
+		{"exact", []string{"application/pdf"}, "application/pdf", true},
+		{"parameters ignored", []string{"text/csv"}, "text/csv; charset=utf-8", true},
+		{"wildcard subtype", []string{"image/*"}, "image/png", true},
+		{"any of several", []string{"text/csv", "application/pdf"}, "application/pdf", true},
+		{"different type", []string{"image/*"}, "application/pdf", false},
+		{"missing type", []string{"application/pdf"}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MimeType(tt.patterns...)(Attachment{MimeType: tt.mimeType})
+			assertEqual(t, got, tt.want, "MIME type match")
+		})
+	}
+}
+
+func newAttachmentMessage(content string, attachments ...*discordgo.MessageAttachment) *Message {
+	return &Message{
+		Content: content,
+		DiscordData: &discordgo.MessageCreate{Message: &discordgo.Message{
+			Content:     content,
+			Attachments: attachments,
+		}},
+	}
+}
+
+func TestHandleMessageWithCommand_AttachmentRoutes(t *testing.T) {
+	// Arrange
+	bot := InitAsDiscordBot("test_token")
+	var route string
+	var matched []Attachment
+	bot.AddHandler(Command{
+		Pattern:     "^ocr",
+		Attachments: ImageAttachments,
+		Handler: func(bot *Bot, message *Message) {
+			route, matched = "ocr", message.MatchedAttachments
+		},
+	})
+	bot.AddHandler(Command{
+		Attachments: MimeType("application/pdf"),
+		Handler: func(bot *Bot, message *Message) {
+			route, matched = "pdf", message.MatchedAttachments
+		},
+	})
+	bot.AddHandler(Command{
+		Pattern: "^ocr",
+		Handler: func(bot *Bot, message *Message) {
+			route, matched = "text", message.MatchedAttachments
+		},
+	})
+	png := &discordgo.MessageAttachment{Filename: "scan.png", ContentType: "image/png"}
+	pdf := &discordgo.MessageAttachment{Filename: "invoice.pdf", ContentType: "application/pdf"}
+
+	// Act & Assert
+	bot.handleMessageWithCommand(newAttachmentMessage("ocr please", png, pdf))
+	assertEqual(t, route, "ocr", "Text pattern and image filter")
+	assertEqual(t, len(matched), 1, "Only matching attachments are passed")
+	assertEqual(t, matched[0].Filename, "scan.png", "Matched attachment")
+
+	bot.handleMessageWithCommand(newAttachmentMessage("here you go", pdf))
+	assertEqual(t, route, "pdf", "MIME type filter without a pattern")
+	assertEqual(t, matched[0].Filename, "invoice.pdf", "Matched attachment")
+
+	bot.handleMessageWithCommand(newAttachmentMessage("ocr please", pdf))
+	assertEqual(t, route, "pdf", "PDF route takes precedence over the text route")
+
+	bot.handleMessageWithCommand(newAttachmentMessage("ocr please"))
+	assertEqual(t, route, "text", "Without attachments only the text route matches")
+	assertEqual(t, len(matched), 0, "No matched attachments")
+}
+
+func TestBot_MatchAttachments_NoPlatformData(t *testing.T) {
+	// Arrange
+	bot := InitAsDiscordBot("test_token")
+
+	// Act
+	matched := bot.matchAttachments(&Message{Content: "hello"}, ImageAttachments)
+
+	// Assert
+	assertEqual(t, len(matched), 0, "Messages without platform data have no attachments")
+}
+
+func TestHandleSlackEventsApi_ImageUploadWithoutText(t *testing.T) {
+	// Arrange
+	bot := InitAsSlackBot("xapp-test", "xoxb-test")
+	var matched []Attachment
+	bot.AddHandler(Command{
+		Attachments: ImageAttachments,
+		Handler: func(bot *Bot, message *Message) {
+			matched = message.MatchedAttachments
+		},
+	})
+	event := slackevents.EventsAPIEvent{
+		InnerEvent: slackevents.EventsAPIInnerEvent{
+			Data: &slackevents.MessageEvent{
+				SubType: "file_share",
+				User:    "U123",
+				Channel: "C456",
+				Files: []slackevents.File{{
+					Name:       "scan.png",
+					Mimetype:   "image/png",
+					URLPrivate: "https://files.slack.com/scan.png",
+				}},
+			},
+		},
+	}
+
+	// Act
+	bot.handleSlackEventsApi(event)
+
+	// Assert
+	assertEqual(t, len(matched), 1, "Image route should fire for an upload without text")
+}
@@ -0,0 +1,115 @@
+package botbooter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+const (
+	defaultMaxAttachmentSize = 25 << 20
+
+	// slackFileHost serves the url_private links of Slack files.
+	slackFileHost = "files.slack.com"
+)
+
+var ErrAttachmentTooLarge = errors.New("attachment exceeds the maximum size")
+
+func (a Attachment) Open(ctx context.Context) (io.ReadCloser, error) {
+	if a.bot == nil {
+		return nil, errors.New("attachment is not bound to a bot")
+	}
+	return a.bot.DownloadAttachment(ctx, a)
+}
+
+func (b *Bot) DownloadAttachment(ctx context.Context, attachment Attachment) (io.ReadCloser, error) {
+	maxSize := b.maxAttachmentSize()
+	if maxSize > 0 && attachment.Size > maxSize {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, attachment.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch b.BotType {
+	case SlackBotType:
+		// Slack private file URLs require the bot token, which must not leak to other hosts.
+		if b.slackTokenAllowed(req.URL) {
+			req.Header.Set("Authorization", "Bearer "+b.slackBotToken)
+		}
+	case DiscordBotType:
+		// Discord attachments are served from a public CDN.
+	default:
+		return nil, fmt.Errorf("unknown bot type")
+	}
+
+	resp, err := b.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("download attachment: unexpected status %s", resp.Status)
+	}
+	if maxSize > 0 && resp.ContentLength > maxSize {
+		resp.Body.Close()
+		return nil, ErrAttachmentTooLarge
+	}
+
+	if maxSize <= 0 {
+		return resp.Body, nil
+	}
+	return &limitedReadCloser{body: resp.Body, remaining: maxSize}, nil
+}
+
+func (b *Bot) slackTokenAllowed(u *url.URL) bool {
+	if u.Scheme == "https" && u.Host == slackFileHost {
+		return true
+	}
+	if b.apiURL == "" {
+		return false
+	}
+
+	api, err := url.Parse(b.apiURL)
+	return err == nil && u.Scheme == api.Scheme && u.Host == api.Host
+}
+
+func (b *Bot) maxAttachmentSize() int64 {
+	if b.MaxAttachmentSize == 0 {
+		return defaultMaxAttachmentSize
+	}
+	return b.MaxAttachmentSize
+}
+
+type limitedReadCloser struct {
+	body      io.ReadCloser
+	remaining int64
+}
+
+func (r *limitedReadCloser) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		// Read one more byte to tell an exact fit apart from an oversized body.
+		var probe [1]byte
+		n, err := r.body.Read(probe[:])
+		if n > 0 {
+			return 0, ErrAttachmentTooLarge
+		}
+		return 0, err
+	}
+
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+	n, err := r.body.Read(p)
+	r.remaining -= int64(n)
+	return n, err
+}
+
+func (r *limitedReadCloser) Close() error {
+	return r.body.Close()
+}
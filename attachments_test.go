@@ -0,0 +1,168 @@
+package botbooter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/slack-go/slack/slackevents"
+)
+
+func TestGetAttachments_Metadata(t *testing.T) {
+	// Arrange
+	bot := InitAsSlackBot("xapp-test", "xoxb-test")
+	message := &Message{SlackData: &slackevents.MessageEvent{Files: []slackevents.File{{
+		ID:         "F123",
+		Name:       "chart.png",
+		Mimetype:   "image/png",
+		Size:       2048,
+		URLPrivate: "https://files.slack.com/chart.png",
+	}}}}
+
+	// Act
+	attachments, err := bot.GetAttachments(message)
+
+	// Assert
+	assertNoError(t, err, "GetAttachments should not fail")
+	assertEqual(t, attachments[0].ID, "F123", "Attachment ID")
+	assertEqual(t, attachments[0].Filename, "chart.png", "Filename")
+	assertEqual(t, attachments[0].MimeType, "image/png", "MIME type")
+	assertEqual(t, attachments[0].Size, int64(2048), "Size")
+	assertTrue(t, attachments[0].IsImage, "Is image")
+}
+
+func TestGetAttachmentsFromDiscordMessage_Metadata(t *testing.T) {
+	// Act
+	attachments := getAttachmentsFromDiscordMessage(&discordgo.Message{Attachments: []*discordgo.MessageAttachment{{
+		ID:          "att123",
+		Filename:    "report.csv",
+		ContentType: "text/csv",
+		Size:        512,
+	}}})
+
+	// Assert
+	assertEqual(t, attachments[0].ID, "att123", "Attachment ID")
+	assertEqual(t, attachments[0].Filename, "report.csv", "Filename")
+	assertEqual(t, attachments[0].MimeType, "text/csv", "MIME type")
+	assertEqual(t, attachments[0].Size, int64(512), "Size")
+	assertFalse(t, attachments[0].IsImage, "Is image")
+}
+
+func TestAttachment_Open_SlackAuthorization(t *testing.T) {
+	// Arrange
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Write([]byte("a,b\n1,2\n"))
+	}))
+	defer server.Close()
+
+	bot, err := NewSlackBot(WithSlackTokens("xapp-test", "xoxb-test"), WithAPIURL(server.URL+"/api/"))
+	assertNoError(t, err, "NewSlackBot should not fail")
+	attachments, _ := bot.GetAttachments(&Message{SlackData: &slackevents.MessageEvent{Files: []slackevents.File{{
+		Name:       "report.csv",
+		URLPrivate: server.URL + "/report.csv",
+	}}}})
+
+	// Act
+	body, err := attachments[0].Open(context.Background())
+	assertNoError(t, err, "Open should not fail")
+	content, readErr := io.ReadAll(body)
+	body.Close()
+
+	// Assert
+	assertNoError(t, readErr, "Reading the attachment should not fail")
+	assertEqual(t, string(content), "a,b\n1,2\n", "Attachment content")
+	assertEqual(t, authorization, "Bearer xoxb-test", "Authorization header")
+}
+
+func TestBot_DownloadAttachment_ForeignHost(t *testing.T) {
+	// Arrange
+	var authorization string
+	foreign := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Write([]byte("content"))
+	}))
+	defer foreign.Close()
+
+	bot, err := NewSlackBot(WithSlackTokens("xapp-test", "xoxb-test"), WithAPIURL("http://slack.internal/api/"))
+	assertNoError(t, err, "NewSlackBot should not fail")
+
+	// Act
+	body, err := bot.DownloadAttachment(context.Background(), Attachment{URL: foreign.URL + "/file"})
+	assertNoError(t, err, "Download should not fail")
+	body.Close()
+
+	// Assert
+	assertEqual(t, authorization, "", "The bot token must not be sent to other hosts")
+}
+
+func TestBot_SlackTokenAllowed(t *testing.T) {
+	// Arrange
+	bot := InitAsSlackBot("xapp-test", "xoxb-test")
+
+	tests := []struct {
+		rawURL string
+		want   bool
+	}{
+		{"https://files.slack.com/files-pri/T1-F1/report.csv", true},
+		{"http://files.slack.com/files-pri/T1-F1/report.csv", false},
+		{"https://files.slack.com.example.com/report.csv", false},
+		{"https://example.com/report.csv", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rawURL, func(t *testing.T) {
+			u, _ := url.Parse(tt.rawURL)
+			assertEqual(t, bot.slackTokenAllowed(u), tt.want, "Token allowed")
+		})
+	}
+}
+
+func TestBot_DownloadAttachment_TooLarge(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Flushing first forces a chunked response without a Content-Length.
+		w.(http.Flusher).Flush()
+		w.Write([]byte(strings.Repeat("x", 64)))
+	}))
+	defer server.Close()
+
+	bot := InitAsDiscordBot("test_token")
+	bot.MaxAttachmentSize = 16
+
+	// Act
+	_, declaredErr := bot.DownloadAttachment(context.Background(), Attachment{URL: server.URL, Size: 64})
+	body, err := bot.DownloadAttachment(context.Background(), Attachment{URL: server.URL})
+	assertNoError(t, err, "Download without a declared size should start")
+	_, readErr := io.ReadAll(body)
+	body.Close()
+
+	// Assert
+	assertTrue(t, errors.Is(declaredErr, ErrAttachmentTooLarge), "Declared size should be rejected")
+	assertTrue(t, errors.Is(readErr, ErrAttachmentTooLarge), "Streamed body should be cut off")
+}
+
+func TestBot_DownloadAttachment_Errors(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	bot := InitAsDiscordBot("test_token")
+
+	// Act
+	_, statusErr := bot.DownloadAttachment(context.Background(), Attachment{URL: server.URL})
+	_, unboundErr := Attachment{URL: server.URL}.Open(context.Background())
+
+	// Assert
+	assertError(t, statusErr, "Non-200 responses should fail")
+	assertError(t, unboundErr, "Attachments not returned by GetAttachments cannot be opened")
+}
@@ -1,13 +1,16 @@
 package botbooter
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"regexp"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/slack-go/slack"
@@ -23,28 +26,75 @@ const (
 )
 
 type Bot struct {
-	BotType               BotType
-	DiscordSession        *discordgo.Session
-	SlackClient           *slack.Client
-	SlackSocketClient     *socketmode.Client
-	Commands              []Command
-	UnknownCommandHandler UnknownCommandHandler
-	Middlewares           []Middleware
+	BotType                BotType
+	DiscordSession         *discordgo.Session
+	SlackClient            *slack.Client
+	SlackSocketClient      *socketmode.Client
+	Commands               []Command
+	UnknownCommandHandler  UnknownCommandHandler
+	Middlewares            []Middleware
+	Logger                 Logger
+	ActionHandlers         map[string]InteractionHandler
+	FormHandlers           map[string]FormSubmitHandler
+	ReactionHandlers       map[string]ReactionHandler
+	MessageEditedHandler   MessageEditedHandler
+	MessageDeletedHandler  MessageDeletedHandler
+	RedispatchEdits        bool
+	ReplyInThreadByDefault bool
+	MentionOnly            bool
+	LookupCacheTTL         time.Duration
+	MaxAttachmentSize      int64
+	ShutdownTimeout        time.Duration
+	MaxEventAge            time.Duration
+	Reconnect              ReconnectOptions
+	ConnectHandler         ConnectHandler
+	DisconnectHandler      DisconnectHandler
+	ReconnectingHandler    ReconnectingHandler
+
+	sendQueue           *sendQueue
+	dispatcher          *dispatcher
+	replies             replyTracker
+	directChannels      directChannelCache
+	lookups             lookupCache
+	scheduler           scheduler
+	handlers            handlerTracker
+	metrics             metrics
+	slackCancel         context.CancelFunc
+	connMu              sync.Mutex
+	discordHandlers     sync.Once
+	state               int32
+	sawConnected        int32
+	lastEventAt         int64
+	reconnectAfter      func(time.Duration) <-chan time.Time
+	interactionAckAfter func(time.Duration) <-chan time.Time
+	slackBotUserID      string
+	slackBotToken       string
+	apiURL              string
+	httpClient          *http.Client
 }
 
 type Message struct {
-	UserID      string
-	ChannelID   string
-	Content     string
-	DiscordData *discordgo.MessageCreate
-	SlackData   *slackevents.MessageEvent
+	UserID             string
+	ChannelID          string
+	MessageID          string
+	ThreadID           string
+	Content            string
+	IsDirect           bool
+	MentionsBot        bool
+	Entities           Entities
+	MatchedAttachments []Attachment
+	DiscordData        *discordgo.MessageCreate
+	SlackData          *slackevents.MessageEvent
+
+	edited bool
 }
 
 type CommandHandler func(bot *Bot, message *Message)
 
 type Command struct {
-	Pattern string
-	Handler CommandHandler
+	Pattern     string
+	Attachments AttachmentFilter
+	Handler     CommandHandler
 }
 
 type UnknownCommandHandler func(bot *Bot, message *Message)
@@ -52,23 +102,48 @@ type UnknownCommandHandler func(bot *Bot, message *Message)
 type Middleware func(bot *Bot, message *Message, next CommandHandler)
 
 type Attachment struct {
+	ID        string
+	Filename  string
+	MimeType  string
+	Size      int64
 	IsImage   bool
 	URL       string
 	ExtraData interface{}
+
+	bot *Bot
 }
 
 func (b *Bot) Connect() error {
+	b.handlers.resume()
+	if b.sendQueue != nil {
+		b.sendQueue.resume()
+	}
+	if b.State() != Reconnecting {
+		b.setState(Connecting)
+	}
+
+	var err error
 	switch b.BotType {
 	case SlackBotType:
-		return b.connectSlack()
+		err = b.connectSlack()
 	case DiscordBotType:
-		return b.connectDiscord()
+		err = b.connectDiscord()
 	default:
 		return fmt.Errorf("unknown bot type")
 	}
+	if err != nil {
+		b.scheduler.shutdown()
+	}
+	return err
 }
 
 func (b *Bot) Disconnect() error {
+	b.markDisconnected(nil)
+	b.scheduler.shutdown()
+	if b.sendQueue != nil {
+		b.sendQueue.flush()
+	}
+
 	switch b.BotType {
 	case SlackBotType:
 		return b.disconnectSlack()
@@ -80,32 +155,79 @@ func (b *Bot) Disconnect() error {
 }
 
 func (b *Bot) GetAttachments(message *Message) ([]Attachment, error) {
+	var attachments []Attachment
 	switch b.BotType {
 	case SlackBotType:
-		return getAttachmentsFromSlackMessage(message.SlackData), nil
+		attachments = getAttachmentsFromSlackMessage(message.SlackData)
 	case DiscordBotType:
-		return getAttachmentsFromDiscordMessage(message.DiscordData.Message), nil
+		attachments = getAttachmentsFromDiscordMessage(message.DiscordData.Message)
 	default:
 		return nil, errors.New("unknown bot type")
 	}
+
+	for i := range attachments {
+		attachments[i].bot = b
+	}
+	return attachments, nil
 }
 
 func (b *Bot) SendMessage(channelID string, message string) error {
+	return b.QueueMessage(channelID, message).Wait()
+}
+
+func (b *Bot) postMessage(channelID string, message string) error {
+	_, err := b.postMessageWithID(channelID, message)
+	return err
+}
+
+func (b *Bot) postMessageWithID(channelID string, message string) (string, error) {
+	switch b.BotType {
+	case SlackBotType:
+		_, ts, err := b.SlackClient.PostMessage(
+			channelID,
+			slack.MsgOptionText(message, false),
+		)
+		return ts, err
+	case DiscordBotType:
+		sent, err := b.DiscordSession.ChannelMessageSend(channelID, message)
+		if err != nil {
+			return "", err
+		}
+		return sent.ID, nil
+	default:
+		return "", fmt.Errorf("unknown bot type")
+	}
+}
+
+func (b *Bot) editMessage(channelID, messageID, message string) error {
 	switch b.BotType {
 	case SlackBotType:
-		_, _, err := b.SlackClient.PostMessage(
+		_, _, _, err := b.SlackClient.UpdateMessage(
 			channelID,
+			messageID,
 			slack.MsgOptionText(message, false),
 		)
 		return err
 	case DiscordBotType:
-		_, err := b.DiscordSession.ChannelMessageSend(channelID, message)
+		_, err := b.DiscordSession.ChannelMessageEdit(channelID, messageID, message)
 		return err
 	default:
 		return fmt.Errorf("unknown bot type")
 	}
 }
 
+func (b *Bot) deleteMessage(channelID, messageID string) error {
+	switch b.BotType {
+	case SlackBotType:
+		_, _, err := b.SlackClient.DeleteMessage(channelID, messageID)
+		return err
+	case DiscordBotType:
+		return b.DiscordSession.ChannelMessageDelete(channelID, messageID)
+	default:
+		return fmt.Errorf("unknown bot type")
+	}
+}
+
 func (b *Bot) AddHandler(handler Command) {
 	b.Commands = append(b.Commands, handler)
 }
@@ -115,14 +237,14 @@ func (b *Bot) SetUnknownCommandHandler(handler UnknownCommandHandler) {
 }
 
 func (b *Bot) StartListening() {
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	<-sigCh
-	log.Println("Bot is shutting down...")
-	err := b.Disconnect()
+	<-ctx.Done()
+	b.logger().Info("Bot is shutting down")
+	err := b.shutdown()
 	if err != nil {
-		log.Println("Failed to disconnect:", err)
+		b.logger().Error("Failed to disconnect", "error", err)
 	}
 }
 
@@ -134,14 +256,35 @@ func (b *Bot) handleMessageWithCommand(message *Message) {
 	handler := func(bot *Bot, message *Message) {
 		for _, command := range bot.Commands {
 			matched, err := regexp.MatchString(command.Pattern, message.Content)
-			if err == nil && matched {
-				command.Handler(bot, message)
-				return
+			if err != nil {
+				bot.logger().Warn("Invalid command pattern", "pattern", command.Pattern, "error", err)
+				continue
+			}
+			if !matched {
+				continue
+			}
+			if command.Attachments != nil {
+				attachments := bot.matchAttachments(message, command.Attachments)
+				if len(attachments) == 0 {
+					bot.logger().Debug("Command skipped, no matching attachments", "pattern", command.Pattern, "channel", message.ChannelID)
+					continue
+				}
+				message.MatchedAttachments = attachments
 			}
+			bot.logger().Debug("Command matched", "pattern", command.Pattern, "channel", message.ChannelID, "user", message.UserID)
+			start := time.Now()
+			command.Handler(bot, message)
+			bot.metrics.observeCommand(command.Pattern, time.Since(start))
+			return
 		}
 		if bot.UnknownCommandHandler != nil {
+			bot.logger().Debug("No command matched, calling unknown command handler", "channel", message.ChannelID, "user", message.UserID)
+			start := time.Now()
 			bot.UnknownCommandHandler(bot, message)
+			bot.metrics.observeCommand("unknown", time.Since(start))
+			return
 		}
+		bot.logger().Debug("No command matched", "channel", message.ChannelID, "user", message.UserID)
 	}
 
 	finalHandler := handler
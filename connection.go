@@ -0,0 +1,216 @@
+package botbooter
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
+	"github.com/slack-go/slack"
+)
+
+type ConnectionState int32
+
+const (
+	Disconnected ConnectionState = iota
+	Connecting
+	Connected
+	Reconnecting
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case Disconnected:
+		return "disconnected"
+	case Connecting:
+		return "connecting"
+	case Connected:
+		return "connected"
+	case Reconnecting:
+		return "reconnecting"
+	default:
+		return "unknown"
+	}
+}
+
+type ReconnectOptions struct {
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// MaxAttempts limits consecutive failed attempts; zero retries forever. Authentication
+	// and configuration errors are never retried.
+	MaxAttempts int
+}
+
+type ConnectHandler func(bot *Bot)
+
+type DisconnectHandler func(bot *Bot, err error)
+
+type ReconnectingHandler func(bot *Bot, attempt int, delay time.Duration)
+
+func (b *Bot) OnConnect(handler ConnectHandler) {
+	b.ConnectHandler = handler
+}
+
+func (b *Bot) OnDisconnect(handler DisconnectHandler) {
+	b.DisconnectHandler = handler
+}
+
+func (b *Bot) OnReconnecting(handler ReconnectingHandler) {
+	b.ReconnectingHandler = handler
+}
+
+func (b *Bot) State() ConnectionState {
+	return ConnectionState(atomic.LoadInt32(&b.state))
+}
+
+func (b *Bot) setState(state ConnectionState) ConnectionState {
+	return ConnectionState(atomic.SwapInt32(&b.state, int32(state)))
+}
+
+func (b *Bot) markConnected() {
+	atomic.StoreInt32(&b.sawConnected, 1)
+	if b.setState(Connected) != Connected && b.ConnectHandler != nil {
+		b.ConnectHandler(b)
+	}
+}
+
+func (b *Bot) markDisconnected(err error) {
+	if b.setState(Disconnected) == Connected && b.DisconnectHandler != nil {
+		b.DisconnectHandler(b, err)
+	}
+}
+
+func (b *Bot) markReconnecting(attempt int, delay time.Duration) {
+	b.setState(Reconnecting)
+	b.logger().Info("Reconnecting", "attempt", attempt, "delay", delay)
+	if b.ReconnectingHandler != nil {
+		b.ReconnectingHandler(b, attempt, delay)
+	}
+}
+
+// supervise connects and keeps reconnecting with jittered exponential backoff until
+// ctx is cancelled or the error is fatal. Discord's gateway reconnects on its own once
+// opened, so for Discord it returns after the first successful connect.
+func (b *Bot) supervise(ctx context.Context) error {
+	attempt := 0
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		atomic.StoreInt32(&b.sawConnected, 0)
+		err := b.Connect()
+		if ctx.Err() != nil || err == nil {
+			return nil
+		}
+		b.markDisconnected(err)
+		if isFatalConnectError(err) {
+			return err
+		}
+		b.logger().Warn("Connection failed", "error", err)
+
+		// A connection that came up before failing starts a fresh backoff sequence.
+		if atomic.LoadInt32(&b.sawConnected) == 1 {
+			attempt = 0
+		}
+		attempt++
+		if b.Reconnect.MaxAttempts > 0 && attempt > b.Reconnect.MaxAttempts {
+			return err
+		}
+
+		delay := b.reconnectBackoff(attempt)
+		b.markReconnecting(attempt, delay)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-b.reconnectWait(delay):
+		}
+	}
+}
+
+// Slack errors for bad credentials or app configuration that no retry can fix.
+var fatalSlackErrors = map[string]bool{
+	"invalid_auth":           true,
+	"not_authed":             true,
+	"account_inactive":       true,
+	"token_revoked":          true,
+	"token_expired":          true,
+	"missing_scope":          true,
+	"not_allowed_token_type": true,
+}
+
+func isFatalConnectError(err error) bool {
+	var slackErr slack.SlackErrorResponse
+	if errors.As(err, &slackErr) {
+		return fatalSlackErrors[slackErr.Err]
+	}
+
+	var discordRESTError *discordgo.RESTError
+	if errors.As(err, &discordRESTError) && discordRESTError.Response != nil {
+		code := discordRESTError.Response.StatusCode
+		return code == http.StatusUnauthorized || code == http.StatusForbidden
+	}
+
+	// Gateway close codes for a bad token, sharding or intents.
+	var closeErr *websocket.CloseError
+	if errors.As(err, &closeErr) {
+		switch closeErr.Code {
+		case 4004, 4010, 4011, 4012, 4013, 4014:
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *Bot) reconnectBackoff(attempt int) time.Duration {
+	initial := b.Reconnect.InitialBackoff
+	if initial <= 0 {
+		initial = time.Second
+	}
+	max := b.Reconnect.MaxBackoff
+	if max <= 0 {
+		max = 2 * time.Minute
+	}
+
+	backoff := initial
+	for i := 1; i < attempt && backoff < max; i++ {
+		backoff *= 2
+	}
+	if backoff > max {
+		backoff = max
+	}
+
+	// Half of the delay is randomised so many bots do not reconnect in lockstep.
+	half := backoff / 2
+	if half <= 0 {
+		return backoff
+	}
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func (b *Bot) reconnectWait(d time.Duration) <-chan time.Time {
+	if b.reconnectAfter != nil {
+		return b.reconnectAfter(d)
+	}
+	return time.After(d)
+}
+
+func (b *Bot) handleDiscordConnect(s *discordgo.Session, c *discordgo.Connect) {
+	b.markConnected()
+}
+
+func (b *Bot) handleDiscordDisconnect(s *discordgo.Session, d *discordgo.Disconnect) {
+	// Closing the session ourselves already moved the state to disconnected.
+	if b.State() == Disconnected {
+		return
+	}
+
+	b.markDisconnected(nil)
+	b.markReconnecting(1, 0)
+}
@@ -0,0 +1,186 @@
+package botbooter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+)
+
+func immediately(time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	ch <- time.Now()
+	return ch
+}
+
+func TestBot_Supervise_RetriesWithBackoff(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":false,"error":"service_unavailable"}`))
+	}))
+	defer server.Close()
+
+	bot := InitAsSlackBot("xapp-test", "xoxb-test")
+	bot.SlackClient = slack.New("xoxb-test", slack.OptionAPIURL(server.URL+"/"))
+	bot.Reconnect = ReconnectOptions{InitialBackoff: time.Second, MaxBackoff: 4 * time.Second, MaxAttempts: 3}
+	bot.reconnectAfter = immediately
+	var attempts []int
+	var delays []time.Duration
+	bot.OnReconnecting(func(bot *Bot, attempt int, delay time.Duration) {
+		attempts = append(attempts, attempt)
+		delays = append(delays, delay)
+	})
+
+	// Act
+	err := bot.supervise(context.Background())
+
+	// Assert
+	assertError(t, err, "Supervisor should give up after MaxAttempts")
+	assertEqual(t, len(attempts), 3, "Number of reconnect attempts")
+	assertEqual(t, attempts[2], 3, "Attempt numbers increase")
+	assertTrue(t, delays[0] >= 500*time.Millisecond && delays[0] <= time.Second, "First delay is jittered around the initial backoff")
+	assertTrue(t, delays[2] >= 2*time.Second && delays[2] <= 4*time.Second, "Delay doubles up to the maximum")
+	assertEqual(t, bot.State(), Disconnected, "State after giving up")
+}
+
+func TestBot_Supervise_StopsOnFatalError(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":false,"error":"invalid_auth"}`))
+	}))
+	defer server.Close()
+
+	bot := InitAsSlackBot("xapp-test", "xoxb-test")
+	bot.SlackClient = slack.New("xoxb-test", slack.OptionAPIURL(server.URL+"/"))
+	bot.reconnectAfter = immediately
+	reconnects := 0
+	bot.OnReconnecting(func(bot *Bot, attempt int, delay time.Duration) {
+		reconnects++
+	})
+
+	// Act
+	err := bot.supervise(context.Background())
+
+	// Assert
+	assertError(t, err, "Supervisor should return fatal errors")
+	assertEqual(t, err.Error(), "invalid_auth", "Fatal error")
+	assertEqual(t, reconnects, 0, "Fatal errors should not be retried")
+}
+
+func TestIsFatalConnectError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"slack invalid auth", slack.SlackErrorResponse{Err: "invalid_auth"}, true},
+		{"slack service unavailable", slack.SlackErrorResponse{Err: "service_unavailable"}, false},
+		{"discord unauthorized", &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusUnauthorized}}, true},
+		{"discord bad gateway", &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusBadGateway}}, false},
+		{"discord authentication failed", &websocket.CloseError{Code: 4004}, true},
+		{"discord session timed out", &websocket.CloseError{Code: 4009}, false},
+		{"plain error", errors.New("connection refused"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertEqual(t, isFatalConnectError(tt.err), tt.want, "isFatalConnectError result")
+		})
+	}
+}
+
+func TestBot_Supervise_StopsWhenCancelled(t *testing.T) {
+	// Arrange
+	bot := InitAsSlackBot("xapp-test", "xoxb-test")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	err := bot.supervise(ctx)
+
+	// Assert
+	assertNoError(t, err, "Cancelled supervisor should not fail")
+}
+
+func TestBot_ReconnectBackoff_Cap(t *testing.T) {
+	// Arrange
+	bot := &Bot{Reconnect: ReconnectOptions{InitialBackoff: time.Second, MaxBackoff: 10 * time.Second}}
+
+	// Act
+	delay := bot.reconnectBackoff(50)
+
+	// Assert
+	assertTrue(t, delay >= 5*time.Second && delay <= 10*time.Second, "Backoff is capped at MaxBackoff")
+}
+
+func TestBot_ConnectionHooks(t *testing.T) {
+	// Arrange
+	bot := InitAsSlackBot("xapp-test", "xoxb-test")
+	var events []string
+	bot.OnConnect(func(bot *Bot) {
+		events = append(events, "connect")
+	})
+	bot.OnDisconnect(func(bot *Bot, err error) {
+		events = append(events, "disconnect")
+	})
+	bot.OnReconnecting(func(bot *Bot, attempt int, delay time.Duration) {
+		events = append(events, "reconnecting")
+	})
+
+	// Act
+	bot.handleSlackSocketEvent(socketmode.Event{Type: socketmode.EventTypeConnected})
+	bot.handleSlackSocketEvent(socketmode.Event{Type: socketmode.EventTypeConnected})
+	connected := bot.State()
+	bot.handleSlackSocketEvent(socketmode.Event{Type: socketmode.EventTypeDisconnect})
+	reconnecting := bot.State()
+	bot.handleSlackSocketEvent(socketmode.Event{Type: socketmode.EventTypeConnected})
+
+	// Assert
+	assertEqual(t, connected, Connected, "State after connect")
+	assertEqual(t, reconnecting, Reconnecting, "State after Slack requested a reconnect")
+	assertEqual(t, len(events), 4, "Number of hook calls")
+	assertEqual(t, events[0], "connect", "First hook")
+	assertEqual(t, events[1], "disconnect", "Second hook")
+	assertEqual(t, events[2], "reconnecting", "Third hook")
+	assertEqual(t, events[3], "connect", "Fourth hook")
+}
+
+func TestHandleDiscordDisconnect(t *testing.T) {
+	// Arrange
+	bot := InitAsDiscordBot("test_token")
+	disconnects := 0
+	bot.OnDisconnect(func(bot *Bot, err error) {
+		disconnects++
+	})
+
+	// Act
+	bot.handleDiscordDisconnect(bot.DiscordSession, &discordgo.Disconnect{})
+	ignored := bot.State()
+	bot.handleDiscordConnect(bot.DiscordSession, &discordgo.Connect{})
+	bot.handleDiscordDisconnect(bot.DiscordSession, &discordgo.Disconnect{})
+
+	// Assert
+	assertEqual(t, ignored, Disconnected, "Disconnect while not connected is ignored")
+	assertEqual(t, bot.State(), Reconnecting, "Gateway drop moves to reconnecting")
+	assertEqual(t, disconnects, 1, "Disconnect hook calls")
+}
+
+func TestBot_Disconnect_State(t *testing.T) {
+	// Arrange
+	bot := InitAsDiscordBot("test_token")
+	bot.markConnected()
+
+	// Act
+	bot.Disconnect()
+
+	// Assert
+	assertEqual(t, bot.State(), Disconnected, "State after Disconnect")
+	assertEqual(t, bot.State().String(), "disconnected", "State name")
+}
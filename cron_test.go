@@ -0,0 +1,81 @@
+package botbooter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule_Next(t *testing.T) {
+	// Monday 2024-01-15 08:30 UTC
+	from := time.Date(2024, 1, 15, 8, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2024, 1, 15, 8, 45, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)},
+		{"30 8 * * *", time.Date(2024, 1, 16, 8, 30, 0, 0, time.UTC)},
+		{"0 9 * * sat,sun", time.Date(2024, 1, 20, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 feb *", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1 * 5", time.Date(2024, 1, 19, 12, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{"@every 90m", time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			s, err := parseSchedule(tt.spec, time.UTC)
+			assertNoError(t, err, "Parsing the schedule should not fail")
+			assertTrue(t, s.next(from).Equal(tt.want), "Next run for "+tt.spec+": got "+s.next(from).String())
+		})
+	}
+}
+
+func TestParseSchedule_Timezone(t *testing.T) {
+	// Arrange
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("timezone database not available")
+	}
+	from := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	// Act
+	fromOption, optionErr := parseSchedule("0 9 * * *", location)
+	fromPrefix, prefixErr := parseSchedule("CRON_TZ=America/New_York 0 9 * * *", time.UTC)
+
+	// Assert
+	assertNoError(t, optionErr, "Location option")
+	assertNoError(t, prefixErr, "Timezone prefix")
+	want := time.Date(2024, 1, 15, 14, 0, 0, 0, time.UTC)
+	assertTrue(t, fromOption.next(from).Equal(want), "9am New York from the location option")
+	assertTrue(t, fromPrefix.next(from).Equal(want), "9am New York from the spec prefix")
+}
+
+func TestParseSchedule_Errors(t *testing.T) {
+	specs := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"* * * foo *",
+		"@every 10ms",
+		"@every soon",
+		"TZ=Mars/Olympus 0 9 * * *",
+	}
+
+	for _, spec := range specs {
+		t.Run(spec, func(t *testing.T) {
+			_, err := parseSchedule(spec, time.UTC)
+			assertError(t, err, "Invalid spec should fail")
+		})
+	}
+}
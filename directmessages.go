@@ -0,0 +1,69 @@
+package botbooter
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/slack-go/slack"
+)
+
+type directChannelCache struct {
+	mu       sync.Mutex
+	channels map[string]string
+}
+
+func (c *directChannelCache) get(userID string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	channelID, ok := c.channels[userID]
+	return channelID, ok
+}
+
+func (c *directChannelCache) put(userID, channelID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.channels == nil {
+		c.channels = map[string]string{}
+	}
+	c.channels[userID] = channelID
+}
+
+func (b *Bot) SendDirectMessage(userID string, message string) error {
+	channelID, err := b.directChannel(userID)
+	if err != nil {
+		return err
+	}
+
+	return b.SendMessage(channelID, message)
+}
+
+func (b *Bot) directChannel(userID string) (string, error) {
+	if channelID, ok := b.directChannels.get(userID); ok {
+		return channelID, nil
+	}
+
+	var channelID string
+	switch b.BotType {
+	case SlackBotType:
+		channel, _, _, err := b.SlackClient.OpenConversation(&slack.OpenConversationParameters{
+			Users: []string{userID},
+		})
+		if err != nil {
+			return "", err
+		}
+		channelID = channel.ID
+	case DiscordBotType:
+		channel, err := b.DiscordSession.UserChannelCreate(userID)
+		if err != nil {
+			return "", err
+		}
+		channelID = channel.ID
+	default:
+		return "", fmt.Errorf("unknown bot type")
+	}
+
+	b.directChannels.put(userID, channelID)
+	return channelID, nil
+}
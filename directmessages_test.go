@@ -0,0 +1,105 @@
+package botbooter
+
+import (
+	"testing"
+
+	"github.com/slack-go/slack/slackevents"
+)
+
+func TestBot_directChannel(t *testing.T) {
+	t.Run("CachedChannel", func(t *testing.T) {
+		// Arrange
+		bot := &Bot{BotType: BotType(999)}
+		bot.directChannels.put("user123", "dm123")
+
+		// Act
+		channelID, err := bot.directChannel("user123")
+
+		// Assert
+		assertNoError(t, err, "Cached DM channel should not require an API call")
+		assertEqual(t, channelID, "dm123", "DM channel ID")
+	})
+
+	t.Run("UnknownBotType", func(t *testing.T) {
+		// Arrange
+		bot := &Bot{BotType: BotType(999)}
+
+		// Act
+		_, err := bot.directChannel("user123")
+
+		// Assert
+		assertError(t, err, "Opening a DM with unknown bot type should fail")
+		assertEqual(t, err.Error(), "unknown bot type", "Error message for unknown bot type")
+	})
+}
+
+func TestBot_SendDirectMessage(t *testing.T) {
+	t.Run("SlackBot", func(t *testing.T) {
+		// Arrange
+		bot := InitAsSlackBot("xapp-test", "xoxb-test")
+
+		// Act
+		err := bot.SendDirectMessage("U123", "hi")
+
+		// Assert
+		// We expect an error because we're not actually connected
+		assertError(t, err, "SendDirectMessage without connection should fail")
+		_, cached := bot.directChannels.get("U123")
+		assertFalse(t, cached, "Failed lookups should not be cached")
+	})
+
+	t.Run("UsesCachedChannel", func(t *testing.T) {
+		// Arrange
+		bot := &Bot{BotType: BotType(999)}
+		bot.directChannels.put("user123", "dm123")
+
+		// Act
+		err := bot.SendDirectMessage("user123", "hi")
+
+		// Assert
+		assertError(t, err, "SendDirectMessage with unknown bot type should fail")
+		assertEqual(t, err.Error(), "unknown bot type", "Error message for unknown bot type")
+	})
+}
+
+func TestHandleSlackEventsApi_IsDirect(t *testing.T) {
+	tests := []struct {
+		channelType string
+		want        bool
+	}{
+		{"im", true},
+		{"channel", false},
+		{"mpim", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.channelType, func(t *testing.T) {
+			// Arrange
+			bot := InitAsSlackBot("xapp-test", "xoxb-test")
+			var received *Message
+			bot.AddHandler(Command{
+				Pattern: ".*",
+				Handler: func(bot *Bot, message *Message) {
+					received = message
+				},
+			})
+			event := slackevents.EventsAPIEvent{
+				InnerEvent: slackevents.EventsAPIInnerEvent{
+					Data: &slackevents.MessageEvent{
+						Text:        "hello",
+						User:        "U123",
+						Channel:     "D456",
+						ChannelType: tt.channelType,
+					},
+				},
+			}
+
+			// Act
+			bot.handleSlackEventsApi(event)
+
+			// Assert
+			assertNotNil(t, received, "Handler should be called")
+			assertEqual(t, received.IsDirect, tt.want, "IsDirect flag")
+		})
+	}
+}
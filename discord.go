@@ -1,59 +1,90 @@
 package botbooter
 
 import (
+	"strings"
+
 	"github.com/bwmarrin/discordgo"
 )
 
 func (b *Bot) connectDiscord() error {
-	b.DiscordSession.AddHandler(func(s *discordgo.Session, m *discordgo.MessageCreate) {
-		if m.Author.ID == s.State.User.ID {
-			return
-		}
+	// Connect is retried by the supervisor, so handlers must only be added once.
+	b.discordHandlers.Do(b.addDiscordHandlers)
 
-		message := &Message{
-			UserID:      m.Author.ID,
-			ChannelID:   m.ChannelID,
-			Content:     m.Content,
-			DiscordData: m,
-		}
-
-		b.handleMessageWithCommand(message)
-	})
+	// Previous content of edited and deleted messages is only known for messages in the state cache.
+	wantsHistory := b.RedispatchEdits || b.MessageEditedHandler != nil || b.MessageDeletedHandler != nil
+	if wantsHistory && b.DiscordSession.State != nil && b.DiscordSession.State.MaxMessageCount == 0 {
+		b.DiscordSession.State.MaxMessageCount = 100
+	}
 
 	err := b.DiscordSession.Open()
 	if err != nil {
 		return err
 	}
 
+	b.scheduler.start(b)
 	return nil
 }
 
+func (b *Bot) addDiscordHandlers() {
+	b.DiscordSession.AddHandler(b.handleDiscordMessageCreate)
+	b.DiscordSession.AddHandler(b.handleDiscordInteractionCreate)
+	b.DiscordSession.AddHandler(b.handleDiscordReactionAdd)
+	b.DiscordSession.AddHandler(b.handleDiscordMessageUpdate)
+	b.DiscordSession.AddHandler(b.handleDiscordMessageDelete)
+	b.DiscordSession.AddHandler(b.handleDiscordConnect)
+	b.DiscordSession.AddHandler(b.handleDiscordDisconnect)
+	b.DiscordSession.AddHandler(b.handleDiscordEvent)
+}
+
+func (b *Bot) handleDiscordMessageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if m.Author.ID == s.State.User.ID {
+		b.logger().Debug("Discord message ignored, sent by the bot", "channel", m.ChannelID)
+		return
+	}
+
+	message := &Message{
+		UserID:      m.Author.ID,
+		ChannelID:   m.ChannelID,
+		MessageID:   m.ID,
+		ThreadID:    discordThreadID(s, m.ChannelID),
+		Content:     m.Content,
+		IsDirect:    m.GuildID == "",
+		DiscordData: m,
+	}
+
+	for _, user := range m.Mentions {
+		if user.ID == s.State.User.ID {
+			message.MentionsBot = true
+		}
+	}
+
+	b.dispatchIncoming(message)
+}
+
 func (b *Bot) disconnectDiscord() error {
 	return b.DiscordSession.Close()
 }
 
+// InitAsDiscordBot returns nil if the session cannot be created; NewDiscordBot
+// returns the error instead.
 func InitAsDiscordBot(token string) *Bot {
-	dg, err := discordgo.New("Bot " + token)
+	b, err := newDiscordBot(&botConfig{discordToken: token})
 	if err != nil {
 		return nil
 	}
-
-	return &Bot{
-		BotType:               DiscordBotType,
-		DiscordSession:        dg,
-		Commands:              []Command{},
-		UnknownCommandHandler: nil,
-		SlackClient:           nil,
-		SlackSocketClient:     nil,
-	}
+	return b
 }
 
 func getAttachmentsFromDiscordMessage(m *discordgo.Message) []Attachment {
 	var attachments []Attachment
 
 	for _, attachment := range m.Attachments {
-		isImage := attachment.Width > 0 && attachment.Height > 0
+		isImage := strings.HasPrefix(attachment.ContentType, "image/") || (attachment.Width > 0 && attachment.Height > 0)
 		attachments = append(attachments, Attachment{
+			ID:        attachment.ID,
+			Filename:  attachment.Filename,
+			MimeType:  attachment.ContentType,
+			Size:      int64(attachment.Size),
 			IsImage:   isImage,
 			URL:       attachment.URL,
 			ExtraData: attachment,
@@ -0,0 +1,583 @@
+// Package discordfake runs an in-process fake of the Discord gateway and REST API
+// for end-to-end tests of botbooter Discord bots.
+package discordfake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
+	"github.com/lao/botbooter"
+)
+
+const Token = "fake-token"
+
+type Call struct {
+	Method string
+	Path   string
+	Body   []byte
+}
+
+type Server struct {
+	BotUser *discordgo.User
+	GuildID string
+	// HeartbeatInterval is announced in HELLO and must be set before the bot connects.
+	HeartbeatInterval time.Duration
+
+	http       *httptest.Server
+	upgrader   websocket.Upgrader
+	mu         sync.Mutex
+	changed    *sync.Cond
+	calls      []Call
+	conn       *websocket.Conn
+	writeMu    sync.Mutex
+	identify   *discordgo.Identify
+	heartbeats int
+	rejections int
+	sequence   int64
+	ids        int64
+	users      map[string]*discordgo.User
+	channels   map[string]*discordgo.Channel
+	messages   map[string][]*discordgo.Message
+	closed     bool
+	closeOnce  sync.Once
+}
+
+func NewServer() *Server {
+	s := &Server{
+		BotUser:           &discordgo.User{ID: "100", Username: "botbooter", Bot: true},
+		GuildID:           "200",
+		HeartbeatInterval: 45 * time.Second,
+		users:             map[string]*discordgo.User{},
+		channels:          map[string]*discordgo.Channel{},
+		messages:          map[string][]*discordgo.Message{},
+		ids:               1000,
+	}
+	s.changed = sync.NewCond(&s.mu)
+	s.upgrader.CheckOrigin = func(*http.Request) bool { return true }
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/", s.handleAPI)
+	mux.HandleFunc("/gateway", s.handleGateway)
+	// discordgo appends a trailing slash to the gateway URL.
+	mux.HandleFunc("/gateway/", s.handleGateway)
+	s.http = httptest.NewServer(mux)
+	return s
+}
+
+func (s *Server) Close() {
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		s.closed = true
+		conn := s.conn
+		s.changed.Broadcast()
+		s.mu.Unlock()
+
+		if conn != nil {
+			conn.Close()
+		}
+		s.http.Close()
+	})
+}
+
+func (s *Server) URL() string {
+	return s.http.URL
+}
+
+func (s *Server) GatewayURL() string {
+	return "ws" + strings.TrimPrefix(s.http.URL, "http") + "/gateway"
+}
+
+// Options configures NewDiscordBot to talk to the fake server.
+func (s *Server) Options() []botbooter.Option {
+	return []botbooter.Option{
+		botbooter.WithDiscordToken(Token),
+		botbooter.WithAPIURL(s.URL()),
+	}
+}
+
+// Attach points a bot created with InitAsDiscordBot at the fake server.
+func (s *Server) Attach(bot *botbooter.Bot) error {
+	return bot.Reconfigure(botbooter.WithAPIURL(s.URL()))
+}
+
+func (s *Server) AddUser(user *discordgo.User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[user.ID] = user
+}
+
+func (s *Server) AddChannel(channel *discordgo.Channel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.channels[channel.ID] = channel
+}
+
+// RejectConnections makes the next n gateway connection attempts fail.
+func (s *Server) RejectConnections(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rejections = n
+}
+
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Call(nil), s.calls...)
+}
+
+// Identify returns the IDENTIFY payload sent by the bot, or nil before it connected.
+func (s *Server) Identify() *discordgo.Identify {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.identify
+}
+
+func (s *Server) Heartbeats() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.heartbeats
+}
+
+// Messages returns the messages the bot has sent to channelID.
+func (s *Server) Messages(channelID string) []*discordgo.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*discordgo.Message(nil), s.messages[channelID]...)
+}
+
+// WaitForMessage waits until the bot has sent n messages to channelID and returns the nth.
+func (s *Server) WaitForMessage(channelID string, n int, timeout time.Duration) (*discordgo.Message, error) {
+	var message *discordgo.Message
+	err := s.waitFor(timeout, func() bool {
+		if len(s.messages[channelID]) < n {
+			return false
+		}
+		message = s.messages[channelID][n-1]
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("waiting for message %d in %s: %w", n, channelID, err)
+	}
+	return message, nil
+}
+
+// WaitForConnection waits until the bot has identified on the gateway.
+func (s *Server) WaitForConnection(timeout time.Duration) error {
+	return s.waitFor(timeout, func() bool {
+		return s.conn != nil && s.identify != nil
+	})
+}
+
+func (s *Server) waitFor(timeout time.Duration, condition func() bool) error {
+	timer := time.AfterFunc(timeout, func() {
+		s.mu.Lock()
+		s.changed.Broadcast()
+		s.mu.Unlock()
+	})
+	defer timer.Stop()
+
+	deadline := time.Now().Add(timeout)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for !condition() {
+		if s.closed {
+			return errors.New("server closed")
+		}
+		if !time.Now().Before(deadline) {
+			return errors.New("timed out")
+		}
+		s.changed.Wait()
+	}
+	return nil
+}
+
+// SendMessage dispatches MESSAGE_CREATE as if userID had typed content in channelID.
+func (s *Server) SendMessage(channelID, userID, content string) (*discordgo.Message, error) {
+	s.mu.Lock()
+	author, ok := s.users[userID]
+	if !ok {
+		author = &discordgo.User{ID: userID, Username: userID}
+	}
+	guildID := s.GuildID
+	if channel, ok := s.channels[channelID]; ok && (channel.Type == discordgo.ChannelTypeDM || channel.Type == discordgo.ChannelTypeGroupDM) {
+		guildID = ""
+	}
+	s.mu.Unlock()
+
+	message := s.newMessage(channelID, guildID, author, content)
+	return message, s.Dispatch("MESSAGE_CREATE", message)
+}
+
+// Dispatch sends an op 0 event of eventType with data as its payload.
+func (s *Server) Dispatch(eventType string, data interface{}) error {
+	s.mu.Lock()
+	s.sequence++
+	sequence := s.sequence
+	s.mu.Unlock()
+
+	return s.writeJSON(map[string]interface{}{
+		"op": 0,
+		"s":  sequence,
+		"t":  eventType,
+		"d":  data,
+	})
+}
+
+func (s *Server) newMessage(channelID, guildID string, author *discordgo.User, content string) *discordgo.Message {
+	return &discordgo.Message{
+		ID:        s.nextID(),
+		ChannelID: channelID,
+		GuildID:   guildID,
+		Author:    author,
+		Content:   content,
+		Timestamp: time.Now().UTC(),
+	}
+}
+
+func (s *Server) nextID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ids++
+	return fmt.Sprint(s.ids)
+}
+
+func (s *Server) writeJSON(v interface{}) error {
+	s.mu.Lock()
+	conn := s.conn
+	s.mu.Unlock()
+	if conn == nil {
+		return errors.New("no gateway connection")
+	}
+
+	return s.write(conn, v)
+}
+
+func (s *Server) write(conn *websocket.Conn, v interface{}) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	return conn.WriteJSON(v)
+}
+
+type gatewayPayload struct {
+	Op   int             `json:"op"`
+	Data json.RawMessage `json:"d"`
+}
+
+func (s *Server) handleGateway(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	reject := s.rejections > 0
+	if reject {
+		s.rejections--
+	}
+	s.mu.Unlock()
+	if reject {
+		http.Error(w, "gateway unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	err = s.write(conn, map[string]interface{}{
+		"op": 10,
+		"d":  map[string]interface{}{"heartbeat_interval": s.HeartbeatInterval.Milliseconds()},
+	})
+	if err != nil {
+		return
+	}
+
+	for {
+		var payload gatewayPayload
+		if err := conn.ReadJSON(&payload); err != nil {
+			break
+		}
+
+		switch payload.Op {
+		case 1:
+			s.mu.Lock()
+			s.heartbeats++
+			s.changed.Broadcast()
+			s.mu.Unlock()
+			s.write(conn, map[string]interface{}{"op": 11})
+		case 2:
+			s.identified(conn, payload.Data)
+		}
+	}
+
+	s.mu.Lock()
+	if s.conn == conn {
+		s.conn = nil
+	}
+	s.changed.Broadcast()
+	s.mu.Unlock()
+}
+
+func (s *Server) identified(conn *websocket.Conn, data json.RawMessage) {
+	// The presence in discordgo's Identify does not round-trip through JSON, so only
+	// the fields tests care about are decoded.
+	var payload struct {
+		Token          string           `json:"token"`
+		Compress       bool             `json:"compress"`
+		LargeThreshold int              `json:"large_threshold"`
+		Shard          *[2]int          `json:"shard"`
+		Intents        discordgo.Intent `json:"intents"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		conn.Close()
+		return
+	}
+	identify := discordgo.Identify{
+		Token:          payload.Token,
+		Compress:       payload.Compress,
+		LargeThreshold: payload.LargeThreshold,
+		Shard:          payload.Shard,
+		Intents:        payload.Intents,
+	}
+
+	s.mu.Lock()
+	previous := s.conn
+	s.conn = conn
+	s.sequence = 0
+	s.mu.Unlock()
+	if previous != nil && previous != conn {
+		previous.Close()
+	}
+
+	// READY goes out before the connection is reported so that tests never race it.
+	s.Dispatch("READY", map[string]interface{}{
+		"v":          10,
+		"user":       s.BotUser,
+		"session_id": "fake-session",
+		"guilds":     []interface{}{},
+	})
+
+	s.mu.Lock()
+	s.identify = &identify
+	s.changed.Broadcast()
+	s.mu.Unlock()
+}
+
+func (s *Server) handleAPI(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/v"+discordgo.APIVersion)
+	s.mu.Lock()
+	s.calls = append(s.calls, Call{Method: r.Method, Path: path, Body: body})
+	s.changed.Broadcast()
+	s.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bot ") && path != "/gateway" {
+		writeError(w, http.StatusUnauthorized, 0, "401: Unauthorized")
+		return
+	}
+
+	r.Body = io.NopCloser(strings.NewReader(string(body)))
+	s.route(w, r, strings.Split(strings.Trim(path, "/"), "/"))
+}
+
+func (s *Server) route(w http.ResponseWriter, r *http.Request, parts []string) {
+	switch {
+	case r.Method == http.MethodGet && match(parts, "gateway"):
+		writeJSON(w, http.StatusOK, map[string]string{"url": s.GatewayURL()})
+	case r.Method == http.MethodGet && match(parts, "gateway", "bot"):
+		writeJSON(w, http.StatusOK, map[string]interface{}{"url": s.GatewayURL(), "shards": 1})
+	case r.Method == http.MethodPost && match(parts, "channels", "*", "messages"):
+		s.createMessage(w, r, parts[1])
+	case r.Method == http.MethodPatch && match(parts, "channels", "*", "messages", "*"):
+		s.editMessage(w, r, parts[1], parts[3])
+	case r.Method == http.MethodDelete && match(parts, "channels", "*", "messages", "*"):
+		s.deleteMessage(w, parts[1], parts[3])
+	case (r.Method == http.MethodPut || r.Method == http.MethodDelete) && match(parts, "channels", "*", "messages", "*", "reactions", "*", "@me"):
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && match(parts, "channels", "*"):
+		s.mu.Lock()
+		channel, ok := s.channels[parts[1]]
+		s.mu.Unlock()
+		if !ok {
+			writeError(w, http.StatusNotFound, discordgo.ErrCodeUnknownChannel, "Unknown Channel")
+			return
+		}
+		writeJSON(w, http.StatusOK, channel)
+	case r.Method == http.MethodGet && match(parts, "users", "*"):
+		s.mu.Lock()
+		user, ok := s.users[parts[1]]
+		s.mu.Unlock()
+		if !ok {
+			writeError(w, http.StatusNotFound, discordgo.ErrCodeUnknownUser, "Unknown User")
+			return
+		}
+		writeJSON(w, http.StatusOK, user)
+	case r.Method == http.MethodPost && match(parts, "users", "@me", "channels"):
+		s.createDirectChannel(w, r)
+	case r.Method == http.MethodPost && match(parts, "interactions", "*", "*", "callback"):
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotFound, 0, "404: Not Found")
+	}
+}
+
+func match(parts []string, pattern ...string) bool {
+	if len(parts) != len(pattern) {
+		return false
+	}
+	for i, p := range pattern {
+		if p != "*" && p != parts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) createMessage(w http.ResponseWriter, r *http.Request, channelID string) {
+	var send discordgo.MessageSend
+	var attachments []*discordgo.MessageAttachment
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			writeError(w, http.StatusBadRequest, 0, err.Error())
+			return
+		}
+		if err := json.Unmarshal([]byte(r.FormValue("payload_json")), &send); err != nil {
+			writeError(w, http.StatusBadRequest, 0, err.Error())
+			return
+		}
+		for _, headers := range r.MultipartForm.File {
+			for _, header := range headers {
+				attachments = append(attachments, &discordgo.MessageAttachment{
+					ID:          s.nextID(),
+					Filename:    header.Filename,
+					ContentType: header.Header.Get("Content-Type"),
+					Size:        int(header.Size),
+				})
+			}
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&send); err != nil {
+		writeError(w, http.StatusBadRequest, 0, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	guildID := s.GuildID
+	if channel, ok := s.channels[channelID]; ok {
+		guildID = channel.GuildID
+	}
+	s.mu.Unlock()
+
+	message := s.newMessage(channelID, guildID, s.BotUser, send.Content)
+	message.Embeds = send.Embeds
+	message.Components = send.Components
+	message.Attachments = attachments
+	if send.Reference != nil {
+		message.MessageReference = send.Reference
+	}
+
+	s.mu.Lock()
+	s.messages[channelID] = append(s.messages[channelID], message)
+	s.changed.Broadcast()
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, message)
+	// Like Discord, echo the bot's own message back over the gateway.
+	s.Dispatch("MESSAGE_CREATE", message)
+}
+
+func (s *Server) editMessage(w http.ResponseWriter, r *http.Request, channelID, messageID string) {
+	var edit struct {
+		Content *string `json:"content"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&edit); err != nil {
+		writeError(w, http.StatusBadRequest, 0, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, message := range s.messages[channelID] {
+		if message.ID == messageID {
+			if edit.Content != nil {
+				message.Content = *edit.Content
+			}
+			edited := time.Now().UTC()
+			message.EditedTimestamp = &edited
+			writeJSON(w, http.StatusOK, message)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, discordgo.ErrCodeUnknownMessage, "Unknown Message")
+}
+
+func (s *Server) deleteMessage(w http.ResponseWriter, channelID, messageID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := s.messages[channelID]
+	for i, message := range messages {
+		if message.ID == messageID {
+			s.messages[channelID] = append(messages[:i:i], messages[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, discordgo.ErrCodeUnknownMessage, "Unknown Message")
+}
+
+func (s *Server) createDirectChannel(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		RecipientID string `json:"recipient_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, 0, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	recipient, ok := s.users[body.RecipientID]
+	if !ok {
+		recipient = &discordgo.User{ID: body.RecipientID, Username: body.RecipientID}
+	}
+	channel := &discordgo.Channel{
+		ID:         "dm-" + body.RecipientID,
+		Type:       discordgo.ChannelTypeDM,
+		Recipients: []*discordgo.User{recipient},
+	}
+	s.channels[channel.ID] = channel
+	writeJSON(w, http.StatusOK, channel)
+}
+
+func writeError(w http.ResponseWriter, status, code int, message string) {
+	writeJSON(w, status, map[string]interface{}{"code": code, "message": message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
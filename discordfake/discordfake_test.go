@@ -0,0 +1,329 @@
+package discordfake
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/lao/botbooter"
+)
+
+const timeout = 5 * time.Second
+
+func connect(t *testing.T, server *Server, bot *botbooter.Bot) {
+	t.Helper()
+
+	if err := bot.Connect(); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() {
+		bot.Disconnect()
+	})
+
+	if err := server.WaitForConnection(timeout); err != nil {
+		t.Fatalf("bot did not identify on the gateway: %v", err)
+	}
+}
+
+func TestEndToEnd_EchoCommand(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+
+	bot := botbooter.InitAsDiscordBot("test-token")
+	if err := server.Attach(bot); err != nil {
+		t.Fatal(err)
+	}
+	bot.AddHandler(botbooter.Command{
+		Pattern: "^echo ",
+		Handler: func(bot *botbooter.Bot, message *botbooter.Message) {
+			bot.SendMessage(message.ChannelID, "You said: "+strings.TrimPrefix(message.Content, "echo "))
+		},
+	})
+	connect(t, server, bot)
+
+	// Act
+	_, err := server.SendMessage("300", "400", "echo hello")
+	if err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	reply, err := server.WaitForMessage("300", 1, timeout)
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Content != "You said: hello" {
+		t.Errorf("reply = %q, want %q", reply.Content, "You said: hello")
+	}
+	if reply.Author.ID != server.BotUser.ID {
+		t.Errorf("reply author = %q, want the bot", reply.Author.ID)
+	}
+}
+
+func TestEndToEnd_Handshake(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	server.HeartbeatInterval = 20 * time.Millisecond
+	defer server.Close()
+
+	bot, err := botbooter.NewDiscordBot(append(server.Options(), botbooter.WithIntents(discordgo.IntentsGuildMessages))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	connect(t, server, bot)
+	err = server.waitFor(timeout, func() bool { return server.heartbeats >= 3 })
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected repeated heartbeats: %v", err)
+	}
+	identify := server.Identify()
+	if identify.Token != "Bot "+Token {
+		t.Errorf("identify token = %q, want %q", identify.Token, "Bot "+Token)
+	}
+	if identify.Intents != discordgo.IntentsGuildMessages {
+		t.Errorf("identify intents = %v, want %v", identify.Intents, discordgo.IntentsGuildMessages)
+	}
+	if bot.State() != botbooter.Connected {
+		t.Errorf("State() = %v, want %v", bot.State(), botbooter.Connected)
+	}
+	if bot.DiscordSession.State.User.ID != server.BotUser.ID {
+		t.Errorf("READY user = %q, want %q", bot.DiscordSession.State.User.ID, server.BotUser.ID)
+	}
+}
+
+func TestEndToEnd_IgnoresOwnMessages(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+
+	var handled int32
+	bot := botbooter.InitAsDiscordBot("test-token")
+	if err := server.Attach(bot); err != nil {
+		t.Fatal(err)
+	}
+	bot.SetUnknownCommandHandler(func(bot *botbooter.Bot, message *botbooter.Message) {
+		atomic.AddInt32(&handled, 1)
+		bot.SendMessage(message.ChannelID, "unknown command")
+	})
+	connect(t, server, bot)
+
+	// Act
+	server.SendMessage("300", "400", "hello")
+	_, err := server.WaitForMessage("300", 1, timeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = server.WaitForMessage("300", 2, 200*time.Millisecond)
+
+	// Assert
+	if err == nil {
+		t.Error("bot replied to its own message")
+	}
+	if got := atomic.LoadInt32(&handled); got != 1 {
+		t.Errorf("handled %d messages, want 1", got)
+	}
+}
+
+func TestEndToEnd_ReconnectRunsHandlersOnce(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+	server.RejectConnections(2)
+
+	var handled int32
+	bot := botbooter.InitAsDiscordBot("test-token")
+	if err := server.Attach(bot); err != nil {
+		t.Fatal(err)
+	}
+	bot.AddHandler(botbooter.Command{
+		Pattern: "^ping$",
+		Handler: func(bot *botbooter.Bot, message *botbooter.Message) {
+			atomic.AddInt32(&handled, 1)
+			bot.SendMessage(message.ChannelID, "pong")
+		},
+	})
+
+	// Act
+	if err := bot.Connect(); err == nil {
+		t.Fatal("expected the first connect to fail")
+	}
+	if err := bot.Connect(); err == nil {
+		t.Fatal("expected the second connect to fail")
+	}
+	connect(t, server, bot)
+	server.SendMessage("300", "400", "ping")
+	server.SendMessage("300", "400", "ping")
+	_, err := server.WaitForMessage("300", 2, timeout)
+	time.Sleep(100 * time.Millisecond)
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(&handled); got != 2 {
+		t.Errorf("handler ran %d times for 2 messages, want 2", got)
+	}
+	if got := len(server.Messages("300")); got != 2 {
+		t.Errorf("bot sent %d replies, want 2", got)
+	}
+}
+
+func TestEndToEnd_DirectMessage(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+	server.AddChannel(&discordgo.Channel{ID: "500", Type: discordgo.ChannelTypeDM})
+
+	isDirect := make(chan bool, 1)
+	bot := botbooter.InitAsDiscordBot("test-token")
+	if err := server.Attach(bot); err != nil {
+		t.Fatal(err)
+	}
+	bot.SetUnknownCommandHandler(func(bot *botbooter.Bot, message *botbooter.Message) {
+		isDirect <- message.IsDirect
+	})
+	connect(t, server, bot)
+
+	// Act
+	server.SendMessage("500", "400", "hi")
+
+	// Assert
+	select {
+	case direct := <-isDirect:
+		if !direct {
+			t.Error("message in a DM channel should be direct")
+		}
+	case <-time.After(timeout):
+		t.Fatal("handler was not called")
+	}
+}
+
+func TestEndToEnd_Run(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+
+	bot := botbooter.InitAsDiscordBot("test-token")
+	if err := server.Attach(bot); err != nil {
+		t.Fatal(err)
+	}
+	bot.AddHandler(botbooter.Command{
+		Pattern: "^ping$",
+		Handler: func(bot *botbooter.Bot, message *botbooter.Message) {
+			bot.SendMessage(message.ChannelID, "pong")
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- bot.Run(ctx)
+	}()
+	if err := server.WaitForConnection(timeout); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	server.SendMessage("300", "400", "ping")
+	reply, err := server.WaitForMessage("300", 1, timeout)
+	cancel()
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Content != "pong" {
+		t.Errorf("reply = %q, want %q", reply.Content, "pong")
+	}
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		t.Fatal("Run did not return after the context was cancelled")
+	}
+}
+
+func TestSendDirectMessage(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+
+	bot, err := botbooter.NewDiscordBot(server.Options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err = bot.SendDirectMessage("400", "hello")
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := server.Messages("dm-400")
+	if len(messages) != 1 || messages[0].Content != "hello" {
+		t.Errorf("unexpected direct messages: %+v", messages)
+	}
+}
+
+func TestSendFile(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+
+	bot, err := botbooter.NewDiscordBot(server.Options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err = bot.SendFile("300", "report.csv", strings.NewReader("a,b\n1,2\n"), botbooter.FileOptions{Caption: "Daily report"})
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := server.Messages("300")
+	if len(messages) != 1 {
+		t.Fatalf("messages = %d, want 1", len(messages))
+	}
+	if messages[0].Content != "Daily report" {
+		t.Errorf("caption = %q, want %q", messages[0].Content, "Daily report")
+	}
+	if len(messages[0].Attachments) != 1 || messages[0].Attachments[0].Filename != "report.csv" {
+		t.Errorf("unexpected attachments: %+v", messages[0].Attachments)
+	}
+}
+
+func TestGetUser(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+	server.AddUser(&discordgo.User{ID: "400", Username: "ada"})
+
+	bot, err := botbooter.NewDiscordBot(server.Options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	user, err := bot.GetUser("400")
+	_, missingErr := bot.GetUser("404")
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Name != "ada" {
+		t.Errorf("unexpected user: %+v", user)
+	}
+	if missingErr == nil {
+		t.Error("expected an error for an unknown user")
+	}
+}
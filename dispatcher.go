@@ -0,0 +1,184 @@
+package botbooter
+
+import (
+	"sync"
+	"time"
+)
+
+type DispatchOrdering int
+
+const (
+	UnorderedDispatch DispatchOrdering = iota
+	PerChannelDispatch
+	PerUserDispatch
+)
+
+type QueueFullPolicy int
+
+const (
+	BlockWhenFull QueueFullPolicy = iota
+	DropWhenFull
+	ReplyBusyWhenFull
+)
+
+const defaultBusyMessage = "I'm busy right now, please try again in a moment."
+
+type DispatcherOptions struct {
+	Workers     int
+	QueueSize   int
+	Ordering    DispatchOrdering
+	WhenFull    QueueFullPolicy
+	BusyMessage string
+}
+
+type dispatchLane struct {
+	pending []func()
+}
+
+type dispatcher struct {
+	opts        DispatcherOptions
+	mu          sync.Mutex
+	space       *sync.Cond
+	queued      int
+	lanes       map[string]*dispatchLane
+	workers     chan struct{}
+	lastStarted time.Time
+}
+
+func newDispatcher(opts DispatcherOptions) *dispatcher {
+	if opts.Workers <= 0 {
+		opts.Workers = 8
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 100
+	}
+	if opts.BusyMessage == "" {
+		opts.BusyMessage = defaultBusyMessage
+	}
+
+	d := &dispatcher{
+		opts:    opts,
+		lanes:   map[string]*dispatchLane{},
+		workers: make(chan struct{}, opts.Workers),
+	}
+	d.space = sync.NewCond(&d.mu)
+	return d
+}
+
+// EnableDispatcher runs message handlers on a bounded worker pool instead of the
+// goroutine that received the event.
+func (b *Bot) EnableDispatcher(opts DispatcherOptions) {
+	b.dispatcher = newDispatcher(opts)
+}
+
+func (d *dispatcher) key(message *Message) string {
+	switch d.opts.Ordering {
+	case PerChannelDispatch:
+		return message.ChannelID
+	case PerUserDispatch:
+		return message.UserID
+	default:
+		return ""
+	}
+}
+
+// submit queues job, returning false if the queue is full and the policy is not to block.
+func (d *dispatcher) submit(key string, job func()) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for d.queued >= d.opts.QueueSize {
+		if d.opts.WhenFull != BlockWhenFull {
+			return false
+		}
+		d.space.Wait()
+	}
+	if d.queued == 0 {
+		// Progress is measured from when the queue stopped being empty.
+		d.lastStarted = time.Now()
+	}
+	d.queued++
+
+	if key == "" {
+		go d.run(job)
+		return true
+	}
+
+	lane, ok := d.lanes[key]
+	if !ok {
+		lane = &dispatchLane{}
+		d.lanes[key] = lane
+		go d.drainLane(key, lane)
+	}
+	lane.pending = append(lane.pending, job)
+	return true
+}
+
+func (d *dispatcher) run(job func()) {
+	d.workers <- struct{}{}
+	defer func() { <-d.workers }()
+
+	d.mu.Lock()
+	d.queued--
+	d.lastStarted = time.Now()
+	d.space.Signal()
+	d.mu.Unlock()
+
+	job()
+}
+
+func (d *dispatcher) drainLane(key string, lane *dispatchLane) {
+	for {
+		d.mu.Lock()
+		if len(lane.pending) == 0 {
+			delete(d.lanes, key)
+			d.mu.Unlock()
+			return
+		}
+		job := lane.pending[0]
+		lane.pending = lane.pending[1:]
+		d.mu.Unlock()
+
+		d.run(job)
+	}
+}
+
+func (b *Bot) dispatchMessage(message *Message) {
+	if b.dispatcher == nil {
+		b.handleMessageWithCommand(message)
+		return
+	}
+
+	// The handler slot is held until the queued job finishes so shutdown waits for it.
+	if !b.handlers.begin() {
+		return
+	}
+	accepted := b.dispatcher.submit(b.dispatcher.key(message), func() {
+		defer b.handlers.end()
+		b.handleMessageWithCommand(message)
+	})
+	if accepted {
+		return
+	}
+	b.handlers.end()
+	b.metrics.incDropped()
+
+	switch b.dispatcher.opts.WhenFull {
+	case ReplyBusyWhenFull:
+		b.logger().Warn("Dispatcher queue full, replying busy", "channel", message.ChannelID, "user", message.UserID)
+		err := b.SendMessage(message.ChannelID, b.dispatcher.opts.BusyMessage)
+		if err != nil {
+			b.logger().Error("Failed to send busy reply", "channel", message.ChannelID, "error", err)
+		}
+	default:
+		b.logger().Warn("Dispatcher queue full, dropping message", "channel", message.ChannelID, "user", message.UserID)
+	}
+}
+
+// wedged reports whether jobs are queued but none has started for longer than timeout.
+func (d *dispatcher) wedged(timeout time.Duration) (queued int, stuck bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.queued, d.queued > 0 && time.Since(d.lastStarted) > timeout
+}
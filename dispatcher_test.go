@@ -0,0 +1,144 @@
+package botbooter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+func TestDispatcher_PerChannelOrdering(t *testing.T) {
+	// Arrange
+	bot := &Bot{BotType: BotType(999)}
+	bot.EnableDispatcher(DispatcherOptions{Workers: 4, Ordering: PerChannelDispatch})
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	bot.AddHandler(Command{
+		Pattern: ".*",
+		Handler: func(bot *Bot, message *Message) {
+			defer wg.Done()
+			if message.Content == "first" {
+				time.Sleep(20 * time.Millisecond)
+			}
+			mu.Lock()
+			order = append(order, message.Content)
+			mu.Unlock()
+		},
+	})
+
+	// Act
+	wg.Add(2)
+	bot.dispatchIncoming(&Message{ChannelID: "C1", Content: "first"})
+	bot.dispatchIncoming(&Message{ChannelID: "C1", Content: "second"})
+	wg.Wait()
+
+	// Assert
+	assertEqual(t, len(order), 2, "Both messages handled")
+	assertEqual(t, order[0], "first", "Messages in a channel keep their order")
+	assertEqual(t, order[1], "second", "Messages in a channel keep their order")
+}
+
+func TestDispatcher_RunsConcurrently(t *testing.T) {
+	// Arrange
+	bot := &Bot{BotType: BotType(999)}
+	bot.EnableDispatcher(DispatcherOptions{Workers: 2})
+	release := make(chan struct{})
+	started := make(chan string, 2)
+	bot.AddHandler(Command{
+		Pattern: ".*",
+		Handler: func(bot *Bot, message *Message) {
+			started <- message.Content
+			<-release
+		},
+	})
+
+	// Act
+	bot.dispatchIncoming(&Message{ChannelID: "C1", Content: "slow"})
+	bot.dispatchIncoming(&Message{ChannelID: "C1", Content: "fast"})
+
+	// Assert
+	for i := 0; i < 2; i++ {
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatal("A slow handler should not block other messages")
+		}
+	}
+	close(release)
+}
+
+func TestDispatcher_DropWhenFull(t *testing.T) {
+	// Arrange
+	bot := &Bot{BotType: BotType(999)}
+	bot.EnableDispatcher(DispatcherOptions{Workers: 1, QueueSize: 1, WhenFull: DropWhenFull})
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	var mu sync.Mutex
+	handled := 0
+	bot.AddHandler(Command{
+		Pattern: ".*",
+		Handler: func(bot *Bot, message *Message) {
+			mu.Lock()
+			handled++
+			mu.Unlock()
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+			<-release
+		},
+	})
+	bot.dispatchIncoming(&Message{Content: "running"})
+	<-started
+
+	// Act
+	bot.dispatchIncoming(&Message{Content: "queued"})
+	bot.dispatchIncoming(&Message{Content: "dropped"})
+	close(release)
+	<-bot.handlers.drain()
+
+	// Assert
+	assertEqual(t, handled, 2, "Messages beyond the queue size are dropped")
+}
+
+func TestDispatcher_ReplyBusyWhenFull(t *testing.T) {
+	// Arrange
+	busy := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		busy <- r.Form.Get("text")
+		w.Write([]byte(`{"ok":true,"channel":"C1","ts":"1"}`))
+	}))
+	defer server.Close()
+
+	bot := InitAsSlackBot("xapp-test", "xoxb-test")
+	bot.SlackClient = slack.New("xoxb-test", slack.OptionAPIURL(server.URL+"/"))
+	bot.EnableDispatcher(DispatcherOptions{Workers: 1, QueueSize: 1, WhenFull: ReplyBusyWhenFull, BusyMessage: "Busy!"})
+	bot.dispatcher.queued = 1
+
+	// Act
+	bot.dispatchIncoming(&Message{ChannelID: "C1", Content: "hello"})
+
+	// Assert
+	select {
+	case text := <-busy:
+		assertEqual(t, text, "Busy!", "Busy reply text")
+	case <-time.After(time.Second):
+		t.Fatal("Busy reply should be sent")
+	}
+}
+
+func TestDispatcher_Defaults(t *testing.T) {
+	// Act
+	d := newDispatcher(DispatcherOptions{})
+
+	// Assert
+	assertEqual(t, cap(d.workers), 8, "Default workers")
+	assertEqual(t, d.opts.QueueSize, 100, "Default queue size")
+	assertEqual(t, d.opts.BusyMessage, defaultBusyMessage, "Default busy message")
+	assertEqual(t, d.key(&Message{ChannelID: "C1", UserID: "U1"}), "", "Unordered dispatch key")
+}
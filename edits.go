@@ -0,0 +1,226 @@
+package botbooter
+
+import (
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/slack-go/slack/slackevents"
+)
+
+const maxTrackedReplies = 1000
+
+type MessageEdit struct {
+	Message    *Message
+	OldContent string
+}
+
+type MessageDeletion struct {
+	ChannelID   string
+	MessageID   string
+	OldContent  string
+	SlackData   *slackevents.MessageEvent
+	DiscordData *discordgo.MessageDelete
+}
+
+type MessageEditedHandler func(bot *Bot, edit *MessageEdit)
+
+type MessageDeletedHandler func(bot *Bot, deletion *MessageDeletion)
+
+type trackedReply struct {
+	ChannelID string
+	MessageID string
+}
+
+type replyTracker struct {
+	mu      sync.Mutex
+	replies map[string]trackedReply
+	order   []string
+}
+
+func (t *replyTracker) get(key string) (trackedReply, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	reply, ok := t.replies[key]
+	return reply, ok
+}
+
+func (t *replyTracker) put(key string, reply trackedReply) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.replies == nil {
+		t.replies = map[string]trackedReply{}
+	}
+	if _, ok := t.replies[key]; !ok {
+		t.order = append(t.order, key)
+	}
+	t.replies[key] = reply
+
+	for len(t.order) > maxTrackedReplies {
+		delete(t.replies, t.order[0])
+		t.order = t.order[1:]
+	}
+}
+
+func (t *replyTracker) remove(key string) (trackedReply, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	reply, ok := t.replies[key]
+	delete(t.replies, key)
+	return reply, ok
+}
+
+func replyKey(channelID, messageID string) string {
+	return channelID + "/" + messageID
+}
+
+func (b *Bot) OnMessageEdited(handler MessageEditedHandler) {
+	b.MessageEditedHandler = handler
+}
+
+func (b *Bot) OnMessageDeleted(handler MessageDeletedHandler) {
+	b.MessageDeletedHandler = handler
+}
+
+func (b *Bot) Reply(message *Message, text string) error {
+	return b.send(message.ChannelID, func() error {
+		key := replyKey(message.ChannelID, message.MessageID)
+
+		if message.edited {
+			if reply, ok := b.replies.get(key); ok {
+				return b.editMessage(reply.ChannelID, reply.MessageID, text)
+			}
+		}
+
+		replyChannelID, replyID, err := b.postReply(message, text)
+		if err != nil {
+			return err
+		}
+		if b.RedispatchEdits && message.MessageID != "" {
+			b.replies.put(key, trackedReply{ChannelID: replyChannelID, MessageID: replyID})
+		}
+		return nil
+	}).Wait()
+}
+
+func (b *Bot) handleMessageEdit(edit *MessageEdit) {
+	if !b.handlers.begin() {
+		return
+	}
+	defer b.handlers.end()
+
+	if b.MessageEditedHandler != nil {
+		b.MessageEditedHandler(b, edit)
+	}
+
+	if b.RedispatchEdits {
+		edit.Message.edited = true
+		b.dispatchIncoming(edit.Message)
+	}
+}
+
+func (b *Bot) handleMessageDeletion(deletion *MessageDeletion) {
+	if !b.handlers.begin() {
+		return
+	}
+	defer b.handlers.end()
+
+	if b.MessageDeletedHandler != nil {
+		b.MessageDeletedHandler(b, deletion)
+	}
+
+	if b.RedispatchEdits {
+		if reply, ok := b.replies.remove(replyKey(deletion.ChannelID, deletion.MessageID)); ok {
+			b.deleteMessage(reply.ChannelID, reply.MessageID)
+		}
+	}
+}
+
+func (b *Bot) handleSlackMessageChanged(ev *slackevents.MessageEvent) {
+	if ev.Message == nil || ev.Message.BotID != "" || ev.Message.SubType == "bot_message" {
+		return
+	}
+
+	edit := &MessageEdit{}
+	if ev.PreviousMessage != nil {
+		edit.OldContent = ev.PreviousMessage.Text
+		// Link unfurls also produce message_changed events without any text change.
+		if edit.OldContent == ev.Message.Text {
+			return
+		}
+	}
+
+	edited := *ev.Message
+	if edited.Channel == "" {
+		edited.Channel = ev.Channel
+	}
+	edit.Message = &Message{
+		UserID:    edited.User,
+		ChannelID: edited.Channel,
+		MessageID: edited.TimeStamp,
+		ThreadID:  edited.ThreadTimeStamp,
+		Content:   edited.Text,
+		IsDirect:  ev.ChannelType == "im",
+		SlackData: &edited,
+	}
+
+	b.handleMessageEdit(edit)
+}
+
+func (b *Bot) handleSlackMessageDeleted(ev *slackevents.MessageEvent) {
+	deletion := &MessageDeletion{
+		ChannelID: ev.Channel,
+		SlackData: ev,
+	}
+	if ev.PreviousMessage != nil {
+		deletion.MessageID = ev.PreviousMessage.TimeStamp
+		deletion.OldContent = ev.PreviousMessage.Text
+	}
+
+	b.handleMessageDeletion(deletion)
+}
+
+func (b *Bot) handleDiscordMessageUpdate(s *discordgo.Session, m *discordgo.MessageUpdate) {
+	// Embed resolution updates carry no author and no content change.
+	if m.Author == nil || m.Author.Bot {
+		return
+	}
+	if s.State != nil && s.State.User != nil && m.Author.ID == s.State.User.ID {
+		return
+	}
+
+	edit := &MessageEdit{
+		Message: &Message{
+			UserID:      m.Author.ID,
+			ChannelID:   m.ChannelID,
+			MessageID:   m.ID,
+			ThreadID:    discordThreadID(s, m.ChannelID),
+			Content:     m.Content,
+			IsDirect:    m.GuildID == "",
+			DiscordData: &discordgo.MessageCreate{Message: m.Message},
+		},
+	}
+	if m.BeforeUpdate != nil {
+		edit.OldContent = m.BeforeUpdate.Content
+		if edit.OldContent == m.Content {
+			return
+		}
+	}
+
+	b.handleMessageEdit(edit)
+}
+
+func (b *Bot) handleDiscordMessageDelete(s *discordgo.Session, m *discordgo.MessageDelete) {
+	deletion := &MessageDeletion{
+		ChannelID:   m.ChannelID,
+		MessageID:   m.ID,
+		DiscordData: m,
+	}
+	if m.BeforeDelete != nil {
+		deletion.OldContent = m.BeforeDelete.Content
+	}
+
+	b.handleMessageDeletion(deletion)
+}
@@ -0,0 +1,215 @@
+package botbooter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/slack-go/slack/slackevents"
+)
+
+func newSlackMessageChangedEvent(oldText, newText string) slackevents.EventsAPIEvent {
+	return slackevents.EventsAPIEvent{
+		InnerEvent: slackevents.EventsAPIInnerEvent{
+			Data: &slackevents.MessageEvent{
+				SubType: "message_changed",
+				Channel: "C456",
+				Message: &slackevents.MessageEvent{
+					User:      "U123",
+					Text:      newText,
+					TimeStamp: "1700000000.000100",
+				},
+				PreviousMessage: &slackevents.MessageEvent{
+					User:      "U123",
+					Text:      oldText,
+					TimeStamp: "1700000000.000100",
+				},
+			},
+		},
+	}
+}
+
+func TestHandleSlackEventsApi_MessageChanged(t *testing.T) {
+	t.Run("CallsEditHook", func(t *testing.T) {
+		// Arrange
+		bot := InitAsSlackBot("xapp-test", "xoxb-test")
+		var received *MessageEdit
+		bot.OnMessageEdited(func(bot *Bot, edit *MessageEdit) {
+			received = edit
+		})
+		commandCalled := false
+		bot.AddHandler(Command{
+			Pattern: "^hello",
+			Handler: func(bot *Bot, message *Message) {
+				commandCalled = true
+			},
+		})
+
+		// Act
+		bot.handleSlackEventsApi(newSlackMessageChangedEvent("helo", "hello"))
+
+		// Assert
+		assertNotNil(t, received, "Edit hook should be called")
+		assertEqual(t, received.OldContent, "helo", "Old content")
+		assertEqual(t, received.Message.Content, "hello", "New content")
+		assertEqual(t, received.Message.ChannelID, "C456", "Channel ID")
+		assertFalse(t, commandCalled, "Edits should not be re-dispatched by default")
+	})
+
+	t.Run("RedispatchesEdits", func(t *testing.T) {
+		// Arrange
+		bot := InitAsSlackBot("xapp-test", "xoxb-test")
+		bot.RedispatchEdits = true
+		var dispatched *Message
+		bot.AddHandler(Command{
+			Pattern: "^hello",
+			Handler: func(bot *Bot, message *Message) {
+				dispatched = message
+			},
+		})
+
+		// Act
+		bot.handleSlackEventsApi(newSlackMessageChangedEvent("helo", "hello"))
+
+		// Assert
+		assertNotNil(t, dispatched, "Edited command should be re-dispatched")
+		assertTrue(t, dispatched.edited, "Re-dispatched message should be marked as edited")
+	})
+
+	t.Run("IgnoresUnfurls", func(t *testing.T) {
+		// Arrange
+		bot := InitAsSlackBot("xapp-test", "xoxb-test")
+		hookCalled := false
+		bot.OnMessageEdited(func(bot *Bot, edit *MessageEdit) {
+			hookCalled = true
+		})
+
+		// Act
+		bot.handleSlackEventsApi(newSlackMessageChangedEvent("https://example.com", "https://example.com"))
+
+		// Assert
+		assertFalse(t, hookCalled, "Edit hook should not be called when the text did not change")
+	})
+}
+
+func TestHandleSlackEventsApi_MessageDeleted(t *testing.T) {
+	// Arrange
+	bot := InitAsSlackBot("xapp-test", "xoxb-test")
+	var received *MessageDeletion
+	bot.OnMessageDeleted(func(bot *Bot, deletion *MessageDeletion) {
+		received = deletion
+	})
+	event := slackevents.EventsAPIEvent{
+		InnerEvent: slackevents.EventsAPIInnerEvent{
+			Data: &slackevents.MessageEvent{
+				SubType: "message_deleted",
+				Channel: "C456",
+				PreviousMessage: &slackevents.MessageEvent{
+					Text:      "hello",
+					TimeStamp: "1700000000.000100",
+				},
+			},
+		},
+	}
+
+	// Act
+	bot.handleSlackEventsApi(event)
+
+	// Assert
+	assertNotNil(t, received, "Delete hook should be called")
+	assertEqual(t, received.ChannelID, "C456", "Channel ID")
+	assertEqual(t, received.MessageID, "1700000000.000100", "Message ID")
+	assertEqual(t, received.OldContent, "hello", "Old content")
+}
+
+func TestHandleDiscordMessageUpdate(t *testing.T) {
+	// Arrange
+	bot := InitAsDiscordBot("test_token")
+	session := &discordgo.Session{State: discordgo.NewState()}
+	session.State.User = &discordgo.User{ID: "bot123"}
+	var received []*MessageEdit
+	bot.OnMessageEdited(func(bot *Bot, edit *MessageEdit) {
+		received = append(received, edit)
+	})
+
+	// Act
+	bot.handleDiscordMessageUpdate(session, &discordgo.MessageUpdate{
+		Message: &discordgo.Message{
+			ID:        "message123",
+			ChannelID: "channel123",
+			Content:   "hello",
+			Author:    &discordgo.User{ID: "user123"},
+		},
+		BeforeUpdate: &discordgo.Message{Content: "helo"},
+	})
+	bot.handleDiscordMessageUpdate(session, &discordgo.MessageUpdate{
+		Message: &discordgo.Message{ID: "message456", ChannelID: "channel123"},
+	})
+	bot.handleDiscordMessageUpdate(session, &discordgo.MessageUpdate{
+		Message: &discordgo.Message{
+			ID:      "message789",
+			Content: "reply",
+			Author:  &discordgo.User{ID: "bot123"},
+		},
+	})
+
+	// Assert
+	assertEqual(t, len(received), 1, "Only user edits should call the hook")
+	assertEqual(t, received[0].OldContent, "helo", "Old content")
+	assertEqual(t, received[0].Message.Content, "hello", "New content")
+	assertEqual(t, received[0].Message.DiscordData.ID, "message123", "Message ID")
+}
+
+func TestHandleDiscordMessageDelete(t *testing.T) {
+	// Arrange
+	bot := InitAsDiscordBot("test_token")
+	var received *MessageDeletion
+	bot.OnMessageDeleted(func(bot *Bot, deletion *MessageDeletion) {
+		received = deletion
+	})
+
+	// Act
+	bot.handleDiscordMessageDelete(&discordgo.Session{}, &discordgo.MessageDelete{
+		Message:      &discordgo.Message{ID: "message123", ChannelID: "channel123"},
+		BeforeDelete: &discordgo.Message{Content: "hello"},
+	})
+
+	// Assert
+	assertNotNil(t, received, "Delete hook should be called")
+	assertEqual(t, received.MessageID, "message123", "Message ID")
+	assertEqual(t, received.OldContent, "hello", "Old content")
+}
+
+func TestReplyTracker(t *testing.T) {
+	// Arrange
+	tracker := &replyTracker{}
+
+	// Act
+	for i := 0; i < maxTrackedReplies+5; i++ {
+		tracker.put(fmt.Sprintf("C456/%d", i), trackedReply{ChannelID: "C456", MessageID: fmt.Sprint(i)})
+	}
+	_, oldestKept := tracker.get("C456/0")
+	newest, newestKept := tracker.get(fmt.Sprintf("C456/%d", maxTrackedReplies+4))
+	removed, removedOK := tracker.remove("C456/10")
+	_, stillThere := tracker.get("C456/10")
+
+	// Assert
+	assertFalse(t, oldestKept, "Oldest reply should be evicted")
+	assertTrue(t, newestKept, "Newest reply should be kept")
+	assertEqual(t, newest.MessageID, fmt.Sprint(maxTrackedReplies+4), "Newest reply message ID")
+	assertTrue(t, removedOK, "Removed reply should be returned")
+	assertEqual(t, removed.MessageID, "10", "Removed reply message ID")
+	assertFalse(t, stillThere, "Removed reply should be forgotten")
+}
+
+func TestBot_Reply_UnknownBotType(t *testing.T) {
+	// Arrange
+	bot := &Bot{BotType: BotType(999)}
+
+	// Act
+	err := bot.Reply(&Message{ChannelID: "channel123"}, "hi")
+
+	// Assert
+	assertError(t, err, "Reply with unknown bot type should fail")
+	assertEqual(t, err.Error(), "unknown bot type", "Error message for unknown bot type")
+}
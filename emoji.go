@@ -0,0 +1,164 @@
+package botbooter
+
+import "strings"
+
+var emojiUnicodeByName = map[string]string{
+	"white_check_mark":            "✅",
+	"heavy_check_mark":            "✔️",
+	"x":                           "❌",
+	"heavy_multiplication_x":      "✖️",
+	"negative_squared_cross_mark": "❎",
+	"warning":                     "⚠️",
+	"no_entry":                    "⛔",
+	"thumbsup":                    "👍",
+	"thumbsdown":                  "👎",
+	"ok_hand":                     "👌",
+	"clap":                        "👏",
+	"wave":                        "👋",
+	"pray":                        "🙏",
+	"raised_hands":                "🙌",
+	"muscle":                      "💪",
+	"point_up":                    "☝️",
+	"eyes":                        "👀",
+	"heart":                       "❤️",
+	"broken_heart":                "💔",
+	"fire":                        "🔥",
+	"star":                        "⭐",
+	"sparkles":                    "✨",
+	"tada":                        "🎉",
+	"rocket":                      "🚀",
+	"100":                         "💯",
+	"zap":                         "⚡",
+	"bulb":                        "💡",
+	"bell":                        "🔔",
+	"lock":                        "🔒",
+	"unlock":                      "🔓",
+	"key":                         "🔑",
+	"hourglass":                   "⌛",
+	"hourglass_flowing_sand":      "⏳",
+	"stopwatch":                   "⏱️",
+	"alarm_clock":                 "⏰",
+	"calendar":                    "📆",
+	"memo":                        "📝",
+	"pushpin":                     "📌",
+	"paperclip":                   "📎",
+	"mag":                         "🔍",
+	"bug":                         "🐛",
+	"wrench":                      "🔧",
+	"hammer":                      "🔨",
+	"gear":                        "⚙️",
+	"package":                     "📦",
+	"construction":                "🚧",
+	"rotating_light":              "🚨",
+	"question":                    "❓",
+	"exclamation":                 "❗",
+	"heavy_plus_sign":             "➕",
+	"heavy_minus_sign":            "➖",
+	"arrow_up":                    "⬆️",
+	"arrow_down":                  "⬇️",
+	"arrows_counterclockwise":     "🔄",
+	"repeat":                      "🔁",
+	"white_circle":                "⚪",
+	"red_circle":                  "🔴",
+	"large_green_circle":          "🟢",
+	"large_yellow_circle":         "🟡",
+	"smile":                       "😄",
+	"smiley":                      "😃",
+	"grinning":                    "😀",
+	"joy":                         "😂",
+	"slightly_smiling_face":       "🙂",
+	"wink":                        "😉",
+	"thinking_face":               "🤔",
+	"confused":                    "😕",
+	"cry":                         "😢",
+	"sob":                         "😭",
+	"scream":                      "😱",
+	"sweat_smile":                 "😅",
+	"sunglasses":                  "😎",
+	"robot_face":                  "🤖",
+	"coffee":                      "☕",
+	"beer":                        "🍺",
+	"pizza":                       "🍕",
+	"one":                         "1️⃣",
+	"two":                         "2️⃣",
+	"three":                       "3️⃣",
+}
+
+var emojiAliases = map[string]string{
+	"+1":              "thumbsup",
+	"-1":              "thumbsdown",
+	"thumbs_up":       "thumbsup",
+	"thumbs_down":     "thumbsdown",
+	"robot":           "robot_face",
+	"thinking":        "thinking_face",
+	"rotating_lights": "rotating_light",
+	"party_popper":    "tada",
+}
+
+var emojiNameByUnicode = func() map[string]string {
+	names := make(map[string]string, len(emojiUnicodeByName))
+	for name, unicode := range emojiUnicodeByName {
+		names[unicode] = name
+		// Discord frequently omits the variation selector on emoji such as ❤️.
+		names[strings.TrimSuffix(unicode, "\ufe0f")] = name
+	}
+	return names
+}()
+
+// NormalizeEmoji returns the canonical ":name:" form of a Slack name, Discord unicode
+// emoji or Discord custom emoji ("name:id" or "<:name:id>").
+func NormalizeEmoji(emoji string) string {
+	return ":" + emojiName(emoji) + ":"
+}
+
+func emojiName(emoji string) string {
+	emoji = strings.TrimSpace(emoji)
+
+	if name, ok := emojiNameByUnicode[emoji]; ok {
+		return name
+	}
+
+	emoji = strings.TrimPrefix(emoji, "<a:")
+	emoji = strings.TrimPrefix(emoji, "<:")
+	emoji = strings.TrimSuffix(emoji, ">")
+	emoji = strings.Trim(emoji, ":")
+
+	// Slack skin tones ("thumbsup::skin-tone-2") and Discord custom emoji IDs ("party:1234") are dropped.
+	if i := strings.Index(emoji, ":"); i >= 0 {
+		emoji = emoji[:i]
+	}
+
+	if canonical, ok := emojiAliases[emoji]; ok {
+		return canonical
+	}
+	return emoji
+}
+
+func slackEmoji(emoji string) string {
+	return emojiName(emoji)
+}
+
+func discordEmoji(emoji string) string {
+	trimmed := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(emoji), "<a:"), "<:"), ">")
+	if name, id, ok := strings.Cut(strings.Trim(trimmed, ":"), ":"); ok && isSnowflake(id) {
+		// Custom emoji are addressed as "name:id".
+		return name + ":" + id
+	}
+
+	if unicode, ok := emojiUnicodeByName[emojiName(emoji)]; ok {
+		return unicode
+	}
+	return strings.TrimSpace(emoji)
+}
+
+func isSnowflake(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package botbooter
+
+import "testing"
+
+func TestNormalizeEmoji(t *testing.T) {
+	tests := []struct {
+		emoji string
+		want  string
+	}{
+		{":white_check_mark:", ":white_check_mark:"},
+		{"white_check_mark", ":white_check_mark:"},
+		{"✅", ":white_check_mark:"},
+		{"❤️", ":heart:"},
+		{"❤", ":heart:"},
+		{"✖️", ":heavy_multiplication_x:"},
+		{"❌", ":x:"},
+		{"+1", ":thumbsup:"},
+		{"thumbsup::skin-tone-2", ":thumbsup:"},
+		{"party:123456789", ":party:"},
+		{"<:party:123456789>", ":party:"},
+		{"<a:dance:123456789>", ":dance:"},
+		{"custom_team_emoji", ":custom_team_emoji:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.emoji, func(t *testing.T) {
+			assertEqual(t, NormalizeEmoji(tt.emoji), tt.want, "Normalized emoji")
+		})
+	}
+}
+
+func TestSlackEmoji(t *testing.T) {
+	assertEqual(t, slackEmoji("✅"), "white_check_mark", "Unicode emoji")
+	assertEqual(t, slackEmoji(":+1:"), "thumbsup", "Aliased emoji")
+	assertEqual(t, slackEmoji("eyes"), "eyes", "Plain name")
+}
+
+func TestDiscordEmoji(t *testing.T) {
+	assertEqual(t, discordEmoji(":white_check_mark:"), "✅", "Named emoji")
+	assertEqual(t, discordEmoji("+1"), "👍", "Aliased emoji")
+	assertEqual(t, discordEmoji("🚀"), "🚀", "Unicode emoji")
+	assertEqual(t, discordEmoji("<:party:123456789>"), "party:123456789", "Custom emoji")
+	assertEqual(t, discordEmoji("thumbsup::skin-tone-2"), "👍", "Skin tone is dropped")
+}
@@ -0,0 +1,102 @@
+package botbooter
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// discordTransport points discordgo's REST calls, whose endpoints are package-level
+// variables, at another server for this session only.
+type discordTransport struct {
+	base        http.RoundTripper
+	discord     *url.URL
+	apiURL      *url.URL
+	gatewayPath string
+	gatewayURL  string
+}
+
+func newDiscordTransport(base http.RoundTripper, apiURL, gatewayURL string) (*discordTransport, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	discord, err := url.Parse(discordgo.EndpointDiscord)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &discordTransport{
+		base:        base,
+		discord:     discord,
+		gatewayPath: "/" + strings.TrimPrefix(discordgo.EndpointGateway, discordgo.EndpointDiscord),
+		gatewayURL:  gatewayURL,
+	}
+	if apiURL != "" {
+		if t.apiURL, err = url.Parse(apiURL); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func (t *discordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != t.discord.Host {
+		return t.base.RoundTrip(req)
+	}
+
+	if t.gatewayURL != "" && req.URL.Path == t.gatewayPath {
+		body, err := json.Marshal(map[string]string{"url": t.gatewayURL})
+		if err != nil {
+			return nil, err
+		}
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": []string{"application/json"}},
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	if t.apiURL == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	redirected := req.Clone(req.Context())
+	redirected.URL.Scheme = t.apiURL.Scheme
+	redirected.URL.Host = t.apiURL.Host
+	redirected.URL.Path = strings.TrimSuffix(t.apiURL.Path, "/") + req.URL.Path
+	redirected.URL.RawPath = ""
+	redirected.Host = t.apiURL.Host
+	return t.base.RoundTrip(redirected)
+}
+
+func discordHTTPClient(client *http.Client, apiURL, gatewayURL string) (*http.Client, error) {
+	if apiURL == "" && gatewayURL == "" {
+		return client, nil
+	}
+
+	var redirected http.Client
+	if client != nil {
+		redirected = *client
+	} else {
+		redirected = http.Client{Timeout: 20 * time.Second}
+	}
+
+	transport, err := newDiscordTransport(redirected.Transport, apiURL, gatewayURL)
+	if err != nil {
+		return nil, err
+	}
+	redirected.Transport = transport
+	return &redirected, nil
+}
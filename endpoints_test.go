@@ -0,0 +1,94 @@
+package botbooter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewSlackBot_APIURL(t *testing.T) {
+	// Arrange
+	var path, text string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		path, text = r.URL.Path, r.Form.Get("text")
+		w.Write([]byte(`{"ok":true,"channel":"C123","ts":"1700000000.000100"}`))
+	}))
+	defer server.Close()
+
+	bot, err := NewSlackBot(WithSlackTokens("xapp-test", "xoxb-test"), WithAPIURL(server.URL+"/api"))
+	assertNoError(t, err, "NewSlackBot should not fail")
+
+	// Act
+	err = bot.SendMessage("C123", "hello")
+
+	// Assert
+	assertNoError(t, err, "SendMessage should not fail")
+	assertEqual(t, path, "/api/chat.postMessage", "Slack Web API path")
+	assertEqual(t, text, "hello", "Message text")
+}
+
+func TestNewDiscordBot_APIURL(t *testing.T) {
+	// Arrange
+	var path, authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, authorization = r.URL.Path, r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"message123","channel_id":"channel123","content":"hello"}`))
+	}))
+	defer server.Close()
+
+	bot, err := NewDiscordBot(WithDiscordToken("test_token"), WithAPIURL(server.URL))
+	assertNoError(t, err, "NewDiscordBot should not fail")
+
+	// Act
+	err = bot.SendMessage("channel123", "hello")
+
+	// Assert
+	assertNoError(t, err, "SendMessage should not fail")
+	assertEqual(t, path, "/api/v9/channels/channel123/messages", "Discord REST path")
+	assertEqual(t, authorization, "Bot test_token", "Authorization header")
+}
+
+func TestNewDiscordBot_GatewayURL(t *testing.T) {
+	// Arrange
+	bot, err := NewDiscordBot(WithDiscordToken("test_token"), WithGatewayURL("ws://127.0.0.1:9999/gateway"))
+	assertNoError(t, err, "NewDiscordBot should not fail")
+
+	// Act
+	gateway, err := bot.DiscordSession.Gateway()
+
+	// Assert
+	assertNoError(t, err, "Gateway lookup should not reach the network")
+	// discordgo appends a trailing slash to the gateway URL.
+	assertEqual(t, gateway, "ws://127.0.0.1:9999/gateway/", "Gateway URL")
+}
+
+func TestWithGatewayURL_Validation(t *testing.T) {
+	// Act
+	_, schemeErr := NewDiscordBot(WithDiscordToken("test_token"), WithGatewayURL("http://127.0.0.1:9999"))
+	_, slackErr := NewSlackBot(WithSlackTokens("xapp-test", "xoxb-test"), WithGatewayURL("ws://127.0.0.1:9999"))
+
+	// Assert
+	assertError(t, schemeErr, "Gateway URL must be a websocket URL")
+	assertError(t, slackErr, "Gateway URL is Discord only")
+}
+
+func TestDiscordTransport_PassesOtherHosts(t *testing.T) {
+	// Arrange
+	var host string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+	}))
+	defer server.Close()
+	client, err := discordHTTPClient(nil, "http://127.0.0.1:1", "")
+	assertNoError(t, err, "discordHTTPClient should not fail")
+
+	// Act
+	resp, err := client.Get(server.URL + "/attachments/1/2/chart.png")
+
+	// Assert
+	assertNoError(t, err, "Requests to other hosts are not redirected")
+	resp.Body.Close()
+	assertEqual(t, host, server.Listener.Addr().String(), "Request host")
+}
@@ -0,0 +1,83 @@
+package botbooter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var entityPattern = regexp.MustCompile(`<([^<>|\s]+)(?:\|([^<>]*))?>|(https?://[^\s<>]+)`)
+
+type Entities struct {
+	Users    []string
+	Channels []string
+	Roles    []string
+	Links    []Link
+	Emoji    []string
+}
+
+type Link struct {
+	URL   string
+	Label string
+}
+
+func parseEntities(content string) Entities {
+	var entities Entities
+
+	for _, match := range entityPattern.FindAllStringSubmatch(content, -1) {
+		if match[3] != "" {
+			// Discord sends links as plain text.
+			entities.Links = append(entities.Links, Link{URL: strings.TrimRight(match[3], ".,;:!?)")})
+			continue
+		}
+
+		token, label := match[1], match[2]
+		switch {
+		case strings.HasPrefix(token, "@&"):
+			entities.Roles = appendUnique(entities.Roles, token[2:])
+		case strings.HasPrefix(token, "@!"):
+			entities.Users = appendUnique(entities.Users, token[2:])
+		case strings.HasPrefix(token, "@"):
+			entities.Users = appendUnique(entities.Users, token[1:])
+		case strings.HasPrefix(token, "#"):
+			entities.Channels = appendUnique(entities.Channels, token[1:])
+		case strings.HasPrefix(token, "!subteam^"):
+			// Slack user groups are the closest equivalent of Discord roles.
+			entities.Roles = appendUnique(entities.Roles, strings.TrimPrefix(token, "!subteam^"))
+		case strings.HasPrefix(token, ":") || strings.HasPrefix(token, "a:"):
+			entities.Emoji = appendUnique(entities.Emoji, NormalizeEmoji(token))
+		case strings.Contains(token, "://") || strings.HasPrefix(token, "mailto:"):
+			entities.Links = append(entities.Links, Link{URL: token, Label: label})
+		}
+	}
+
+	return entities
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+func (b *Bot) MentionUser(userID string) string {
+	return "<@" + userID + ">"
+}
+
+func (b *Bot) MentionChannel(channelID string) string {
+	return "<#" + channelID + ">"
+}
+
+func (b *Bot) MentionRole(roleID string) (string, error) {
+	switch b.BotType {
+	case SlackBotType:
+		return "<!subteam^" + roleID + ">", nil
+	case DiscordBotType:
+		return "<@&" + roleID + ">", nil
+	default:
+		return "", fmt.Errorf("unknown bot type")
+	}
+}
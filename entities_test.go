@@ -0,0 +1,91 @@
+package botbooter
+
+import (
+	"testing"
+
+	"github.com/slack-go/slack/slackevents"
+)
+
+func TestParseEntities_Slack(t *testing.T) {
+	// Act
+	entities := parseEntities("<@U123> see <#C456|general> and <https://example.com|the docs>, ping <!subteam^S789|@oncall> <@U123|bob>")
+
+	// Assert
+	assertEqual(t, len(entities.Users), 1, "Number of users")
+	assertEqual(t, entities.Users[0], "U123", "User ID")
+	assertEqual(t, len(entities.Channels), 1, "Number of channels")
+	assertEqual(t, entities.Channels[0], "C456", "Channel ID")
+	assertEqual(t, len(entities.Roles), 1, "Number of roles")
+	assertEqual(t, entities.Roles[0], "S789", "User group ID")
+	assertEqual(t, len(entities.Links), 1, "Number of links")
+	assertEqual(t, entities.Links[0].URL, "https://example.com", "Link URL")
+	assertEqual(t, entities.Links[0].Label, "the docs", "Link label")
+}
+
+func TestParseEntities_Discord(t *testing.T) {
+	// Act
+	entities := parseEntities("<@!123> <@&456> in <#789> shipped <:party:112233> see https://example.com/changelog.")
+
+	// Assert
+	assertEqual(t, len(entities.Users), 1, "Number of users")
+	assertEqual(t, entities.Users[0], "123", "User ID")
+	assertEqual(t, len(entities.Roles), 1, "Number of roles")
+	assertEqual(t, entities.Roles[0], "456", "Role ID")
+	assertEqual(t, len(entities.Channels), 1, "Number of channels")
+	assertEqual(t, entities.Channels[0], "789", "Channel ID")
+	assertEqual(t, len(entities.Emoji), 1, "Number of emoji")
+	assertEqual(t, entities.Emoji[0], ":party:", "Emoji name")
+	assertEqual(t, len(entities.Links), 1, "Number of links")
+	assertEqual(t, entities.Links[0].URL, "https://example.com/changelog", "Link URL without trailing punctuation")
+}
+
+func TestParseEntities_PlainText(t *testing.T) {
+	// Act
+	entities := parseEntities("just 2 < 3 and 4 > 1")
+
+	// Assert
+	assertEqual(t, len(entities.Users)+len(entities.Channels)+len(entities.Roles)+len(entities.Links)+len(entities.Emoji), 0, "No entities")
+}
+
+func TestBot_Mentions(t *testing.T) {
+	// Arrange
+	slackBot := InitAsSlackBot("xapp-test", "xoxb-test")
+	discordBot := InitAsDiscordBot("test_token")
+
+	// Act
+	slackRole, slackErr := slackBot.MentionRole("S123")
+	discordRole, discordErr := discordBot.MentionRole("456")
+	_, unknownErr := (&Bot{BotType: BotType(999)}).MentionRole("789")
+
+	// Assert
+	assertEqual(t, slackBot.MentionUser("U123"), "<@U123>", "Slack user mention")
+	assertEqual(t, discordBot.MentionChannel("789"), "<#789>", "Discord channel mention")
+	assertNoError(t, slackErr, "Slack role mention")
+	assertEqual(t, slackRole, "<!subteam^S123>", "Slack user group mention")
+	assertNoError(t, discordErr, "Discord role mention")
+	assertEqual(t, discordRole, "<@&456>", "Discord role mention")
+	assertError(t, unknownErr, "Unknown bot type")
+}
+
+func TestHandleSlackEventsApi_Entities(t *testing.T) {
+	// Arrange
+	bot, received := newSlackMentionTestBot(false)
+
+	// Act
+	bot.handleSlackEventsApi(slackevents.EventsAPIEvent{
+		InnerEvent: slackevents.EventsAPIInnerEvent{
+			Data: &slackevents.MessageEvent{
+				Text:    "deploy api to <#C789|prod> for <@UBOT>",
+				User:    "U123",
+				Channel: "C456",
+			},
+		},
+	})
+
+	// Assert
+	assertEqual(t, len(*received), 1, "Command should be dispatched")
+	entities := (*received)[0].Entities
+	assertEqual(t, len(entities.Users), 1, "Bot mention is kept in entities")
+	assertEqual(t, entities.Users[0], "UBOT", "Mentioned user")
+	assertEqual(t, entities.Channels[0], "C789", "Mentioned channel")
+}
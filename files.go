@@ -0,0 +1,81 @@
+package botbooter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/slack-go/slack"
+)
+
+type FileOptions struct {
+	Caption     string
+	Title       string
+	ThreadID    string
+	ContentType string
+}
+
+func (b *Bot) SendFile(channelID, name string, r io.Reader, opts FileOptions) error {
+	// The content is buffered so the send queue can retry the upload.
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	return b.send(channelID, func() error {
+		return b.uploadFile(channelID, name, content, opts)
+	}).Wait()
+}
+
+func (b *Bot) uploadFile(channelID, name string, content []byte, opts FileOptions) error {
+	switch b.BotType {
+	case SlackBotType:
+		_, err := b.SlackClient.UploadFileV2(slackFileUploadParameters(channelID, name, content, opts))
+		return err
+	case DiscordBotType:
+		targetID := channelID
+		if opts.ThreadID != "" {
+			targetID = opts.ThreadID
+		}
+		_, err := b.DiscordSession.ChannelMessageSendComplex(targetID, discordFileMessage(name, content, opts))
+		return err
+	default:
+		return fmt.Errorf("unknown bot type")
+	}
+}
+
+// Slack retired files.upload, so uploads go through files.getUploadURLExternal and
+// files.completeUploadExternal.
+func slackFileUploadParameters(channelID, name string, content []byte, opts FileOptions) slack.UploadFileV2Parameters {
+	return slack.UploadFileV2Parameters{
+		Reader:          bytes.NewReader(content),
+		FileSize:        len(content),
+		Filename:        name,
+		Title:           firstNonEmpty(opts.Title, name),
+		InitialComment:  opts.Caption,
+		Channel:         channelID,
+		ThreadTimestamp: opts.ThreadID,
+	}
+}
+
+func discordFileMessage(name string, content []byte, opts FileOptions) *discordgo.MessageSend {
+	contentType := opts.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(name))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return &discordgo.MessageSend{
+		Content: opts.Caption,
+		Files: []*discordgo.File{{
+			Name:        name,
+			ContentType: contentType,
+			Reader:      bytes.NewReader(content),
+		}},
+	}
+}
@@ -0,0 +1,73 @@
+package botbooter
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSlackFileUploadParameters(t *testing.T) {
+	// Act
+	params := slackFileUploadParameters("C456", "report.csv", []byte("a,b\n1,2\n"), FileOptions{
+		Caption:  "Weekly report",
+		Title:    "Report",
+		ThreadID: "1700000000.000100",
+	})
+
+	// Assert
+	assertEqual(t, params.Filename, "report.csv", "Filename")
+	assertEqual(t, params.InitialComment, "Weekly report", "Caption")
+	assertEqual(t, params.Title, "Report", "Title")
+	assertEqual(t, params.ThreadTimestamp, "1700000000.000100", "Thread timestamp")
+	assertEqual(t, params.Channel, "C456", "Channel ID")
+	assertEqual(t, params.FileSize, 8, "File size")
+	content, _ := io.ReadAll(params.Reader)
+	assertEqual(t, string(content), "a,b\n1,2\n", "File content")
+}
+
+func TestSlackFileUploadParameters_DefaultTitle(t *testing.T) {
+	// Act
+	params := slackFileUploadParameters("C456", "report.csv", []byte("a,b"), FileOptions{})
+
+	// Assert
+	assertEqual(t, params.Title, "report.csv", "Title defaults to the filename")
+}
+
+func TestDiscordFileMessage(t *testing.T) {
+	// Act
+	message := discordFileMessage("chart.png", []byte("png"), FileOptions{Caption: "Latency"})
+
+	// Assert
+	assertEqual(t, message.Content, "Latency", "Caption")
+	assertEqual(t, len(message.Files), 1, "Number of files")
+	assertEqual(t, message.Files[0].Name, "chart.png", "Filename")
+	assertEqual(t, message.Files[0].ContentType, "image/png", "Content type from extension")
+}
+
+func TestDiscordFileMessage_UnknownExtension(t *testing.T) {
+	// Act
+	message := discordFileMessage("dump", []byte("data"), FileOptions{})
+
+	// Assert
+	assertEqual(t, message.Files[0].ContentType, "application/octet-stream", "Fallback content type")
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("read failed")
+}
+
+func TestBot_SendFile_Errors(t *testing.T) {
+	// Arrange
+	bot := &Bot{BotType: BotType(999)}
+
+	// Act
+	readErr := bot.SendFile("C456", "report.csv", failingReader{}, FileOptions{})
+	typeErr := bot.SendFile("C456", "report.csv", strings.NewReader("a,b"), FileOptions{})
+
+	// Assert
+	assertError(t, readErr, "Reader errors should be returned")
+	assertError(t, typeErr, "Unknown bot type")
+}
@@ -0,0 +1,239 @@
+package botbooter
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+)
+
+type Form struct {
+	ID          string
+	Title       string
+	SubmitLabel string
+	Fields      []FormField
+}
+
+type FormField struct {
+	ID          string
+	Label       string
+	Placeholder string
+	Value       string
+	Multiline   bool
+	Optional    bool
+	MinLength   int
+	MaxLength   int
+}
+
+type FormSubmission struct {
+	FormID      string
+	UserID      string
+	ChannelID   string
+	Values      map[string]string
+	SlackData   *slack.InteractionCallback
+	DiscordData *discordgo.InteractionCreate
+}
+
+type FormErrors map[string]string
+
+type FormSubmitHandler func(bot *Bot, submission *FormSubmission) FormErrors
+
+func (b *Bot) OnFormSubmit(formID string, handler FormSubmitHandler) {
+	if b.FormHandlers == nil {
+		b.FormHandlers = map[string]FormSubmitHandler{}
+	}
+	b.FormHandlers[formID] = handler
+}
+
+func (b *Bot) OpenForm(interaction *Interaction, form *Form) error {
+	switch {
+	case interaction.SlackData != nil:
+		_, err := b.SlackClient.OpenView(interaction.SlackData.TriggerID, form.slackView(interaction.ChannelID))
+		return err
+	case interaction.DiscordData != nil:
+		if !interaction.claimResponse() {
+			return errInteractionResponded
+		}
+		return b.DiscordSession.InteractionRespond(interaction.DiscordData.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseModal,
+			Data: &discordgo.InteractionResponseData{
+				CustomID:   form.ID,
+				Title:      form.Title,
+				Components: form.discordComponents(),
+			},
+		})
+	default:
+		return errors.New("interaction cannot open a form")
+	}
+}
+
+func (b *Bot) handleFormSubmission(submission *FormSubmission) FormErrors {
+	handler, ok := b.FormHandlers[submission.FormID]
+	if !ok {
+		b.logger().Debug("No handler for form", "form", submission.FormID)
+		return nil
+	}
+	if !b.handlers.begin() {
+		return nil
+	}
+	defer b.handlers.end()
+
+	return handler(b, submission)
+}
+
+func (f *Form) slackView(channelID string) slack.ModalViewRequest {
+	view := slack.ModalViewRequest{
+		Type:       slack.VTModal,
+		CallbackID: f.ID,
+		Title:      slack.NewTextBlockObject(slack.PlainTextType, f.Title, false, false),
+		// Slack views do not carry a channel, so it is round-tripped through the metadata.
+		PrivateMetadata: channelID,
+	}
+
+	if f.SubmitLabel != "" {
+		view.Submit = slack.NewTextBlockObject(slack.PlainTextType, f.SubmitLabel, false, false)
+	} else {
+		view.Submit = slack.NewTextBlockObject(slack.PlainTextType, "Submit", false, false)
+	}
+
+	for _, field := range f.Fields {
+		var placeholder *slack.TextBlockObject
+		if field.Placeholder != "" {
+			placeholder = slack.NewTextBlockObject(slack.PlainTextType, field.Placeholder, false, false)
+		}
+
+		element := slack.NewPlainTextInputBlockElement(placeholder, field.ID)
+		element.InitialValue = field.Value
+		element.Multiline = field.Multiline
+		element.MinLength = field.MinLength
+		element.MaxLength = field.MaxLength
+
+		input := slack.NewInputBlock(field.ID,
+			slack.NewTextBlockObject(slack.PlainTextType, field.Label, false, false), nil, element)
+		input.Optional = field.Optional
+
+		view.Blocks.BlockSet = append(view.Blocks.BlockSet, input)
+	}
+
+	return view
+}
+
+func (f *Form) discordComponents() []discordgo.MessageComponent {
+	var rows []discordgo.MessageComponent
+
+	for _, field := range f.Fields {
+		style := discordgo.TextInputShort
+		if field.Multiline {
+			style = discordgo.TextInputParagraph
+		}
+
+		rows = append(rows, discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.TextInput{
+				CustomID:    field.ID,
+				Label:       field.Label,
+				Style:       style,
+				Placeholder: field.Placeholder,
+				Value:       field.Value,
+				Required:    !field.Optional,
+				MinLength:   field.MinLength,
+				MaxLength:   field.MaxLength,
+			},
+		}})
+	}
+
+	return rows
+}
+
+func (b *Bot) handleSlackViewSubmission(evt socketmode.Event, callback *slack.InteractionCallback) {
+	submission := &FormSubmission{
+		FormID:    callback.View.CallbackID,
+		UserID:    callback.User.ID,
+		ChannelID: callback.View.PrivateMetadata,
+		Values:    map[string]string{},
+		SlackData: callback,
+	}
+
+	for _, actions := range callback.View.State.Values {
+		for actionID, action := range actions {
+			submission.Values[actionID] = action.Value
+		}
+	}
+
+	formErrors := b.handleFormSubmission(submission)
+	if len(formErrors) == 0 {
+		b.SlackSocketClient.Ack(*evt.Request)
+		return
+	}
+
+	// Input blocks use the field ID as block ID, so errors map directly onto them.
+	b.SlackSocketClient.Ack(*evt.Request, slack.NewErrorsViewSubmissionResponse(formErrors))
+}
+
+func (b *Bot) handleDiscordModalSubmit(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ModalSubmitData()
+	submission := &FormSubmission{
+		FormID:      data.CustomID,
+		UserID:      discordInteractionUserID(i),
+		ChannelID:   i.ChannelID,
+		Values:      discordModalValues(data.Components),
+		DiscordData: i,
+	}
+
+	formErrors := b.handleFormSubmission(submission)
+	if len(formErrors) == 0 {
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseDeferredMessageUpdate,
+		})
+		return
+	}
+
+	// Discord modals cannot display inline errors, so they are reported in an ephemeral reply.
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: formErrors.String(),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+func discordModalValues(components []discordgo.MessageComponent) map[string]string {
+	values := map[string]string{}
+
+	for _, component := range components {
+		switch c := component.(type) {
+		case *discordgo.ActionsRow:
+			for key, value := range discordModalValues(c.Components) {
+				values[key] = value
+			}
+		case discordgo.ActionsRow:
+			for key, value := range discordModalValues(c.Components) {
+				values[key] = value
+			}
+		case *discordgo.TextInput:
+			values[c.CustomID] = c.Value
+		case discordgo.TextInput:
+			values[c.CustomID] = c.Value
+		}
+	}
+
+	return values
+}
+
+func (e FormErrors) String() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	lines := make([]string, 0, len(fields))
+	for _, field := range fields {
+		lines = append(lines, fmt.Sprintf("%s: %s", field, e[field]))
+	}
+	return strings.Join(lines, "\n")
+}
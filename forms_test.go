@@ -0,0 +1,141 @@
+package botbooter
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+)
+
+func newTestForm() *Form {
+	return &Form{
+		ID:    "incident",
+		Title: "Report incident",
+		Fields: []FormField{
+			{ID: "title", Label: "Title", MaxLength: 80},
+			{ID: "severity", Label: "Severity", Placeholder: "SEV1-SEV3"},
+			{ID: "description", Label: "Description", Multiline: true, Optional: true},
+		},
+	}
+}
+
+func TestForm_SlackView(t *testing.T) {
+	// Arrange
+	form := newTestForm()
+
+	// Act
+	view := form.slackView("C456")
+
+	// Assert
+	assertEqual(t, view.CallbackID, "incident", "View callback ID")
+	assertEqual(t, view.PrivateMetadata, "C456", "View private metadata")
+	assertEqual(t, view.Submit.Text, "Submit", "Default submit label")
+	assertEqual(t, len(view.Blocks.BlockSet), 3, "Number of input blocks")
+	description := view.Blocks.BlockSet[2].(*slack.InputBlock)
+	assertEqual(t, description.BlockID, "description", "Input block ID")
+	assertTrue(t, description.Optional, "Optional field")
+	assertTrue(t, description.Element.(*slack.PlainTextInputBlockElement).Multiline, "Multiline field")
+}
+
+func TestForm_DiscordComponents(t *testing.T) {
+	// Arrange
+	form := newTestForm()
+
+	// Act
+	rows := form.discordComponents()
+
+	// Assert
+	assertEqual(t, len(rows), 3, "Number of component rows")
+	title := rows[0].(discordgo.ActionsRow).Components[0].(discordgo.TextInput)
+	assertEqual(t, title.CustomID, "title", "Text input custom ID")
+	assertEqual(t, title.MaxLength, 80, "Text input max length")
+	assertTrue(t, title.Required, "Required field")
+	description := rows[2].(discordgo.ActionsRow).Components[0].(discordgo.TextInput)
+	assertEqual(t, description.Style, discordgo.TextInputParagraph, "Multiline style")
+	assertFalse(t, description.Required, "Optional field")
+}
+
+func TestBot_OpenForm_UnsupportedInteraction(t *testing.T) {
+	// Arrange
+	bot := &Bot{}
+
+	// Act
+	err := bot.OpenForm(&Interaction{ActionID: "report"}, newTestForm())
+
+	// Assert
+	assertError(t, err, "OpenForm without platform data should fail")
+}
+
+func TestHandleSlackSocketEvent_ViewSubmission(t *testing.T) {
+	// Arrange
+	bot := InitAsSlackBot("xapp-test", "xoxb-test")
+	var received *FormSubmission
+	bot.OnFormSubmit("incident", func(bot *Bot, submission *FormSubmission) FormErrors {
+		received = submission
+		return nil
+	})
+
+	callback := slack.InteractionCallback{
+		Type: slack.InteractionTypeViewSubmission,
+		User: slack.User{ID: "U123"},
+		View: slack.View{
+			CallbackID:      "incident",
+			PrivateMetadata: "C456",
+			State: &slack.ViewState{Values: map[string]map[string]slack.BlockAction{
+				"title":    {"title": {Value: "Database down"}},
+				"severity": {"severity": {Value: "SEV1"}},
+			}},
+		},
+	}
+	evt := socketmode.Event{
+		Type:    socketmode.EventTypeInteractive,
+		Data:    callback,
+		Request: &socketmode.Request{EnvelopeID: "test-envelope"},
+	}
+
+	// Act
+	bot.handleSlackSocketEvent(evt)
+
+	// Assert
+	assertNotNil(t, received, "Submit handler should be called")
+	assertEqual(t, received.FormID, "incident", "Form ID")
+	assertEqual(t, received.UserID, "U123", "User ID")
+	assertEqual(t, received.ChannelID, "C456", "Channel ID")
+	assertEqual(t, received.Values["title"], "Database down", "Title value")
+	assertEqual(t, received.Values["severity"], "SEV1", "Severity value")
+}
+
+func TestDiscordModalValues(t *testing.T) {
+	// Arrange
+	components := []discordgo.MessageComponent{
+		&discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			&discordgo.TextInput{CustomID: "title", Value: "Database down"},
+		}},
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.TextInput{CustomID: "severity", Value: "SEV1"},
+		}},
+	}
+
+	// Act
+	values := discordModalValues(components)
+
+	// Assert
+	assertEqual(t, len(values), 2, "Number of values")
+	assertEqual(t, values["title"], "Database down", "Title value")
+	assertEqual(t, values["severity"], "SEV1", "Severity value")
+}
+
+func TestFormErrors_String(t *testing.T) {
+	// Arrange
+	formErrors := FormErrors{
+		"title":    "Title is too short",
+		"severity": "Unknown severity",
+	}
+
+	// Act
+	text := formErrors.String()
+
+	// Assert
+	assertEqual(t, text, "severity: Unknown severity\ntitle: Title is too short", "Formatted errors")
+}
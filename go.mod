@@ -4,12 +4,12 @@ go 1.19
 
 require (
 	github.com/bwmarrin/discordgo v0.27.1
+	github.com/gorilla/websocket v1.5.0
 	github.com/joho/godotenv v1.5.1
 	github.com/slack-go/slack v0.12.1
 )
 
 require (
-	github.com/gorilla/websocket v1.5.0 // indirect
 	github.com/stretchr/testify v1.7.1 // indirect
 	golang.org/x/crypto v0.7.0 // indirect
 	golang.org/x/sys v0.6.0 // indirect
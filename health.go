@@ -0,0 +1,84 @@
+package botbooter
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const defaultDispatcherStallTimeout = time.Minute
+
+type HealthReport struct {
+	Live                bool     `json:"live"`
+	Ready               bool     `json:"ready"`
+	Platform            string   `json:"platform"`
+	State               string   `json:"state"`
+	LastEventAgeSeconds *float64 `json:"last_event_age_seconds"`
+	DispatcherQueued    int      `json:"dispatcher_queued"`
+	DispatcherWedged    bool     `json:"dispatcher_wedged"`
+	Problems            []string `json:"problems,omitempty"`
+}
+
+func (b *Bot) recordEvent() {
+	atomic.StoreInt64(&b.lastEventAt, time.Now().UnixNano())
+}
+
+// Health reports liveness (the dispatcher is making progress) and readiness (live,
+// connected and, if MaxEventAge is set, receiving events).
+func (b *Bot) Health() HealthReport {
+	report := HealthReport{
+		Live:     true,
+		Platform: b.platform(),
+		State:    b.State().String(),
+	}
+
+	if last := atomic.LoadInt64(&b.lastEventAt); last != 0 {
+		age := time.Since(time.Unix(0, last)).Seconds()
+		report.LastEventAgeSeconds = &age
+		if b.MaxEventAge > 0 && age > b.MaxEventAge.Seconds() {
+			report.Problems = append(report.Problems, "no events received recently")
+		}
+	} else if b.MaxEventAge > 0 && b.State() == Connected {
+		report.Problems = append(report.Problems, "no events received yet")
+	}
+
+	if b.dispatcher != nil {
+		report.DispatcherQueued, report.DispatcherWedged = b.dispatcher.wedged(defaultDispatcherStallTimeout)
+		if report.DispatcherWedged {
+			report.Live = false
+			report.Problems = append(report.Problems, "dispatcher is not making progress")
+		}
+	}
+
+	if b.State() != Connected {
+		report.Problems = append(report.Problems, "not connected")
+	}
+	report.Ready = report.Live && len(report.Problems) == 0
+	return report
+}
+
+// HealthHandler serves the health report as JSON. It answers 503 when the bot is not
+// ready, or with "?probe=live" only when it is not live.
+func (b *Bot) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := b.Health()
+
+		healthy := report.Ready
+		if r.URL.Query().Get("probe") == "live" {
+			healthy = report.Live
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(report)
+	})
+}
+
+func (b *Bot) handleDiscordEvent(s *discordgo.Session, e *discordgo.Event) {
+	b.recordEvent()
+}
@@ -0,0 +1,87 @@
+package botbooter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func getHealth(t *testing.T, bot *Bot, target string) (int, HealthReport) {
+	recorder := httptest.NewRecorder()
+	bot.HealthHandler().ServeHTTP(recorder, httptest.NewRequest("GET", target, nil))
+
+	var report HealthReport
+	err := json.Unmarshal(recorder.Body.Bytes(), &report)
+	assertNoError(t, err, "Health response should be JSON")
+	return recorder.Code, report
+}
+
+func TestBot_HealthHandler_NotConnected(t *testing.T) {
+	// Arrange
+	bot := InitAsSlackBot("xapp-test", "xoxb-test")
+
+	// Act
+	readyCode, report := getHealth(t, bot, "/healthz")
+	liveCode, _ := getHealth(t, bot, "/healthz?probe=live")
+
+	// Assert
+	assertEqual(t, readyCode, http.StatusServiceUnavailable, "Readiness status")
+	assertEqual(t, liveCode, http.StatusOK, "Liveness status")
+	assertTrue(t, report.Live, "Live")
+	assertFalse(t, report.Ready, "Ready")
+	assertEqual(t, report.State, "disconnected", "State")
+	assertEqual(t, report.Platform, "slack", "Platform")
+	assertTrue(t, report.LastEventAgeSeconds == nil, "No events yet")
+}
+
+func TestBot_HealthHandler_Connected(t *testing.T) {
+	// Arrange
+	bot := InitAsDiscordBot("test_token")
+	bot.markConnected()
+	bot.recordEvent()
+
+	// Act
+	code, report := getHealth(t, bot, "/healthz")
+
+	// Assert
+	assertEqual(t, code, http.StatusOK, "Readiness status")
+	assertTrue(t, report.Ready, "Ready")
+	assertNotNil(t, report.LastEventAgeSeconds, "Last event age")
+	assertEqual(t, len(report.Problems), 0, "No problems")
+}
+
+func TestBot_Health_StaleEvents(t *testing.T) {
+	// Arrange
+	bot := InitAsDiscordBot("test_token")
+	bot.MaxEventAge = time.Minute
+	bot.markConnected()
+	bot.lastEventAt = time.Now().Add(-2 * time.Minute).UnixNano()
+
+	// Act
+	report := bot.Health()
+
+	// Assert
+	assertTrue(t, report.Live, "Stale events do not affect liveness")
+	assertFalse(t, report.Ready, "Stale events affect readiness")
+	assertTrue(t, *report.LastEventAgeSeconds >= 120, "Last event age")
+}
+
+func TestBot_Health_WedgedDispatcher(t *testing.T) {
+	// Arrange
+	bot := InitAsDiscordBot("test_token")
+	bot.markConnected()
+	bot.EnableDispatcher(DispatcherOptions{Workers: 1, QueueSize: 10})
+	bot.dispatcher.queued = 3
+	bot.dispatcher.lastStarted = time.Now().Add(-2 * defaultDispatcherStallTimeout)
+
+	// Act
+	code, report := getHealth(t, bot, "/healthz?probe=live")
+
+	// Assert
+	assertEqual(t, code, http.StatusServiceUnavailable, "Liveness status")
+	assertFalse(t, report.Live, "Live")
+	assertTrue(t, report.DispatcherWedged, "Dispatcher wedged")
+	assertEqual(t, report.DispatcherQueued, 3, "Queued jobs")
+}
@@ -0,0 +1,339 @@
+package botbooter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+)
+
+const (
+	// Discord buttons carry no value, so it is appended to the custom ID after this separator.
+	discordCustomIDSeparator = ":"
+	discordCustomIDLength    = 100
+
+	// Discord requires an answer within three seconds, so slower handlers get a deferred one.
+	discordInteractionAckDelay = 2 * time.Second
+)
+
+var errInteractionResponded = errors.New("interaction was already acknowledged")
+
+type ButtonStyle int
+
+const (
+	DefaultButton ButtonStyle = iota
+	PrimaryButton
+	DangerButton
+)
+
+type Button struct {
+	ActionID string
+	Label    string
+	Value    string
+	Style    ButtonStyle
+	URL      string
+}
+
+type SelectMenu struct {
+	ActionID    string
+	Placeholder string
+	Options     []SelectOption
+}
+
+type SelectOption struct {
+	Label       string
+	Value       string
+	Description string
+}
+
+type Interaction struct {
+	ActionID    string
+	Value       string
+	Values      []string
+	UserID      string
+	ChannelID   string
+	MessageID   string
+	SlackData   *slack.InteractionCallback
+	DiscordData *discordgo.InteractionCreate
+
+	mu        sync.Mutex
+	responded bool
+}
+
+// claimResponse reports whether the caller is the first to answer the interaction.
+func (i *Interaction) claimResponse() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.responded {
+		return false
+	}
+	i.responded = true
+	return true
+}
+
+type InteractionHandler func(bot *Bot, interaction *Interaction)
+
+func (b *Bot) OnAction(actionID string, handler InteractionHandler) {
+	if b.ActionHandlers == nil {
+		b.ActionHandlers = map[string]InteractionHandler{}
+	}
+	b.ActionHandlers[actionID] = handler
+}
+
+func (b *Bot) handleInteraction(interaction *Interaction) {
+	handler, ok := b.ActionHandlers[interaction.ActionID]
+	if !ok {
+		b.logger().Debug("No handler for action", "action", interaction.ActionID)
+		return
+	}
+	if !b.handlers.begin() {
+		return
+	}
+	defer b.handlers.end()
+
+	handler(b, interaction)
+}
+
+func (m *RichMessage) slackActionBlock() *slack.ActionBlock {
+	var elements []slack.BlockElement
+
+	for _, button := range m.Buttons {
+		element := slack.NewButtonBlockElement(button.ActionID, button.Value,
+			slack.NewTextBlockObject(slack.PlainTextType, button.Label, true, false))
+		element.URL = button.URL
+		switch button.Style {
+		case PrimaryButton:
+			element.Style = slack.StylePrimary
+		case DangerButton:
+			element.Style = slack.StyleDanger
+		}
+		elements = append(elements, element)
+	}
+
+	for _, menu := range m.SelectMenus {
+		var options []*slack.OptionBlockObject
+		for _, option := range menu.Options {
+			var description *slack.TextBlockObject
+			if option.Description != "" {
+				description = slack.NewTextBlockObject(slack.PlainTextType, option.Description, true, false)
+			}
+			options = append(options, slack.NewOptionBlockObject(option.Value,
+				slack.NewTextBlockObject(slack.PlainTextType, option.Label, true, false), description))
+		}
+		var placeholder *slack.TextBlockObject
+		if menu.Placeholder != "" {
+			placeholder = slack.NewTextBlockObject(slack.PlainTextType, menu.Placeholder, true, false)
+		}
+		elements = append(elements, slack.NewOptionsSelectBlockElement(slack.OptTypeStatic, placeholder, menu.ActionID, options...))
+	}
+
+	if len(elements) == 0 {
+		return nil
+	}
+
+	return slack.NewActionBlock("", elements...)
+}
+
+func (m *RichMessage) discordComponents() ([]discordgo.MessageComponent, error) {
+	var rows []discordgo.MessageComponent
+
+	// Discord allows at most five buttons per row and a select menu needs a row of its own.
+	var buttons []discordgo.MessageComponent
+	for _, button := range m.Buttons {
+		component := discordgo.Button{
+			Label: button.Label,
+			Style: discordgo.SecondaryButton,
+		}
+		switch {
+		case button.URL != "":
+			component.Style = discordgo.LinkButton
+			component.URL = button.URL
+		default:
+			customID, err := discordCustomID(button.ActionID, button.Value)
+			if err != nil {
+				return nil, err
+			}
+			component.CustomID = customID
+			switch button.Style {
+			case PrimaryButton:
+				component.Style = discordgo.PrimaryButton
+			case DangerButton:
+				component.Style = discordgo.DangerButton
+			}
+		}
+		buttons = append(buttons, component)
+
+		if len(buttons) == 5 {
+			rows = append(rows, discordgo.ActionsRow{Components: buttons})
+			buttons = nil
+		}
+	}
+	if len(buttons) > 0 {
+		rows = append(rows, discordgo.ActionsRow{Components: buttons})
+	}
+
+	for _, menu := range m.SelectMenus {
+		if _, err := discordCustomID(menu.ActionID, ""); err != nil {
+			return nil, err
+		}
+		var options []discordgo.SelectMenuOption
+		for _, option := range menu.Options {
+			options = append(options, discordgo.SelectMenuOption{
+				Label:       option.Label,
+				Value:       option.Value,
+				Description: option.Description,
+			})
+		}
+		rows = append(rows, discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.SelectMenu{
+				MenuType:    discordgo.StringSelectMenu,
+				CustomID:    menu.ActionID,
+				Placeholder: menu.Placeholder,
+				Options:     options,
+			},
+		}})
+	}
+
+	return rows, nil
+}
+
+func discordCustomID(actionID, value string) (string, error) {
+	customID := actionID
+	if value != "" {
+		customID += discordCustomIDSeparator + value
+	}
+	if len(customID) > discordCustomIDLength {
+		return "", fmt.Errorf("discord custom ID %q is longer than %d characters", customID, discordCustomIDLength)
+	}
+	return customID, nil
+}
+
+// parseDiscordCustomID prefers the longest registered action ID, so action IDs and
+// values may both contain the separator.
+func (b *Bot) parseDiscordCustomID(customID string) (string, string) {
+	if _, ok := b.ActionHandlers[customID]; ok {
+		return customID, ""
+	}
+
+	actionID, value, _ := strings.Cut(customID, discordCustomIDSeparator)
+	for registered := range b.ActionHandlers {
+		prefix := registered + discordCustomIDSeparator
+		if strings.HasPrefix(customID, prefix) && len(registered) > len(actionID) {
+			actionID, value = registered, customID[len(prefix):]
+		}
+	}
+	return actionID, value
+}
+
+func (b *Bot) handleSlackInteractive(evt socketmode.Event) {
+	callback, ok := evt.Data.(slack.InteractionCallback)
+	if !ok {
+		return
+	}
+
+	switch callback.Type {
+	case slack.InteractionTypeBlockActions:
+		b.SlackSocketClient.Ack(*evt.Request)
+
+		for _, action := range callback.ActionCallback.BlockActions {
+			b.handleInteraction(newSlackInteraction(&callback, action))
+		}
+	case slack.InteractionTypeViewSubmission:
+		b.handleSlackViewSubmission(evt, &callback)
+	}
+}
+
+func newSlackInteraction(callback *slack.InteractionCallback, action *slack.BlockAction) *Interaction {
+	interaction := &Interaction{
+		ActionID:  action.ActionID,
+		Value:     action.Value,
+		UserID:    callback.User.ID,
+		ChannelID: callback.Channel.ID,
+		MessageID: callback.Container.MessageTs,
+		SlackData: callback,
+	}
+
+	if action.SelectedOption.Value != "" {
+		interaction.Value = action.SelectedOption.Value
+		interaction.Values = []string{action.SelectedOption.Value}
+	}
+	for _, option := range action.SelectedOptions {
+		interaction.Values = append(interaction.Values, option.Value)
+	}
+	if interaction.Value == "" && len(interaction.Values) > 0 {
+		interaction.Value = interaction.Values[0]
+	}
+
+	return interaction
+}
+
+func (b *Bot) handleDiscordInteractionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	switch i.Type {
+	case discordgo.InteractionMessageComponent:
+		interaction := b.newDiscordInteraction(i)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			b.handleInteraction(interaction)
+		}()
+
+		// Handlers that open a modal must do so before the deferred answer is sent.
+		select {
+		case <-done:
+		case <-b.interactionAckWait(discordInteractionAckDelay):
+		}
+		if interaction.claimResponse() {
+			s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+				Type: discordgo.InteractionResponseDeferredMessageUpdate,
+			})
+		}
+	case discordgo.InteractionModalSubmit:
+		b.handleDiscordModalSubmit(s, i)
+	}
+}
+
+func (b *Bot) interactionAckWait(d time.Duration) <-chan time.Time {
+	if b.interactionAckAfter != nil {
+		return b.interactionAckAfter(d)
+	}
+	return time.After(d)
+}
+
+func (b *Bot) newDiscordInteraction(i *discordgo.InteractionCreate) *Interaction {
+	data := i.MessageComponentData()
+	actionID, value := b.parseDiscordCustomID(data.CustomID)
+
+	interaction := &Interaction{
+		ActionID:    actionID,
+		Value:       value,
+		Values:      data.Values,
+		UserID:      discordInteractionUserID(i),
+		ChannelID:   i.ChannelID,
+		DiscordData: i,
+	}
+
+	if len(data.Values) > 0 {
+		interaction.Value = data.Values[0]
+	}
+	if i.Message != nil {
+		interaction.MessageID = i.Message.ID
+	}
+
+	return interaction
+}
+
+func discordInteractionUserID(i *discordgo.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+	return ""
+}
@@ -0,0 +1,296 @@
+package botbooter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+)
+
+func newTestInteractiveMessage() *RichMessage {
+	return &RichMessage{
+		Text: "Approve the deploy?",
+		Buttons: []Button{
+			{ActionID: "approve", Label: "Approve", Value: "deploy-42", Style: PrimaryButton},
+			{ActionID: "reject", Label: "Reject", Style: DangerButton},
+			{Label: "Runbook", URL: "https://example.com/runbook"},
+		},
+		SelectMenus: []SelectMenu{
+			{
+				ActionID:    "environment",
+				Placeholder: "Environment",
+				Options: []SelectOption{
+					{Label: "Staging", Value: "staging"},
+					{Label: "Production", Value: "production", Description: "Careful"},
+				},
+			},
+		},
+	}
+}
+
+func TestBot_OnAction(t *testing.T) {
+	// Arrange
+	bot := &Bot{}
+	handlerCalled := false
+
+	// Act
+	bot.OnAction("approve", func(bot *Bot, interaction *Interaction) {
+		handlerCalled = true
+	})
+	bot.handleInteraction(&Interaction{ActionID: "approve"})
+	bot.handleInteraction(&Interaction{ActionID: "unknown"})
+
+	// Assert
+	assertEqual(t, len(bot.ActionHandlers), 1, "Number of action handlers")
+	assertTrue(t, handlerCalled, "Handler should be called for registered action")
+}
+
+func TestRichMessage_SlackActionBlock(t *testing.T) {
+	// Arrange
+	message := newTestInteractiveMessage()
+
+	// Act
+	block := message.slackActionBlock()
+
+	// Assert
+	assertNotNil(t, block, "Action block should be rendered")
+	elements := block.Elements.ElementSet
+	assertEqual(t, len(elements), 4, "Number of action elements")
+	approve := elements[0].(*slack.ButtonBlockElement)
+	assertEqual(t, approve.ActionID, "approve", "Button action ID")
+	assertEqual(t, approve.Value, "deploy-42", "Button value")
+	assertEqual(t, approve.Style, slack.StylePrimary, "Button style")
+	assertEqual(t, elements[2].(*slack.ButtonBlockElement).URL, "https://example.com/runbook", "Link button URL")
+	menu := elements[3].(*slack.SelectBlockElement)
+	assertEqual(t, menu.ActionID, "environment", "Select action ID")
+	assertEqual(t, len(menu.Options), 2, "Number of select options")
+}
+
+func TestRichMessage_SlackActionBlockEmpty(t *testing.T) {
+	// Arrange
+	message := &RichMessage{Text: "no actions"}
+
+	// Act
+	block := message.slackActionBlock()
+
+	// Assert
+	assertTrue(t, block == nil, "Action block should not be rendered without actions")
+}
+
+func TestRichMessage_DiscordComponents(t *testing.T) {
+	// Arrange
+	message := newTestInteractiveMessage()
+	for i := 0; i < 3; i++ {
+		message.Buttons = append(message.Buttons, Button{ActionID: "extra", Label: "Extra"})
+	}
+
+	// Act
+	rows, err := message.discordComponents()
+
+	// Assert
+	assertNoError(t, err, "Components should render")
+	assertEqual(t, len(rows), 3, "Number of component rows")
+	firstRow := rows[0].(discordgo.ActionsRow)
+	assertEqual(t, len(firstRow.Components), 5, "Buttons in first row")
+	approve := firstRow.Components[0].(discordgo.Button)
+	assertEqual(t, approve.CustomID, "approve:deploy-42", "Button custom ID")
+	assertEqual(t, approve.Style, discordgo.PrimaryButton, "Button style")
+	link := firstRow.Components[2].(discordgo.Button)
+	assertEqual(t, link.Style, discordgo.LinkButton, "Link button style")
+	assertEqual(t, link.CustomID, "", "Link button custom ID")
+	menu := rows[2].(discordgo.ActionsRow).Components[0].(discordgo.SelectMenu)
+	assertEqual(t, menu.CustomID, "environment", "Select custom ID")
+	assertEqual(t, len(menu.Options), 2, "Number of select options")
+}
+
+func TestRichMessage_DiscordComponents_CustomIDTooLong(t *testing.T) {
+	// Arrange
+	message := &RichMessage{Buttons: []Button{
+		{ActionID: "approve", Label: "Approve", Value: strings.Repeat("x", 100)},
+	}}
+
+	// Act
+	_, err := message.discordComponents()
+
+	// Assert
+	assertError(t, err, "Custom IDs over 100 characters should be rejected")
+}
+
+func TestParseDiscordCustomID(t *testing.T) {
+	bot := &Bot{}
+	bot.OnAction("approve", func(bot *Bot, interaction *Interaction) {})
+	bot.OnAction("deploy:prod", func(bot *Bot, interaction *Interaction) {})
+	bot.OnAction("deploy", func(bot *Bot, interaction *Interaction) {})
+
+	tests := []struct {
+		customID     string
+		wantActionID string
+		wantValue    string
+	}{
+		{"approve", "approve", ""},
+		{"approve:deploy-42", "approve", "deploy-42"},
+		{"approve:a:b", "approve", "a:b"},
+		{"deploy:prod", "deploy:prod", ""},
+		{"deploy:prod:v1.2", "deploy:prod", "v1.2"},
+		{"deploy:staging", "deploy", "staging"},
+		{"unknown:value", "unknown", "value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.customID, func(t *testing.T) {
+			actionID, value := bot.parseDiscordCustomID(tt.customID)
+			assertEqual(t, actionID, tt.wantActionID, "Action ID")
+			assertEqual(t, value, tt.wantValue, "Value")
+		})
+	}
+}
+
+func TestHandleSlackSocketEvent_BlockActions(t *testing.T) {
+	// Arrange
+	bot := InitAsSlackBot("xapp-test", "xoxb-test")
+	var received *Interaction
+	bot.OnAction("environment", func(bot *Bot, interaction *Interaction) {
+		received = interaction
+	})
+
+	evt := socketmode.Event{
+		Type: socketmode.EventTypeInteractive,
+		Data: slack.InteractionCallback{
+			Type:      slack.InteractionTypeBlockActions,
+			User:      slack.User{ID: "U123"},
+			Channel:   slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "C456"}}},
+			Container: slack.Container{MessageTs: "1700000000.000100"},
+			ActionCallback: slack.ActionCallbacks{
+				BlockActions: []*slack.BlockAction{
+					{
+						ActionID:       "environment",
+						SelectedOption: slack.OptionBlockObject{Value: "production"},
+					},
+				},
+			},
+		},
+		Request: &socketmode.Request{
+			EnvelopeID: "test-envelope",
+		},
+	}
+
+	// Act
+	bot.handleSlackSocketEvent(evt)
+
+	// Assert
+	assertNotNil(t, received, "Handler should be called for block action")
+	assertEqual(t, received.Value, "production", "Selected value")
+	assertEqual(t, received.UserID, "U123", "User ID")
+	assertEqual(t, received.ChannelID, "C456", "Channel ID")
+	assertEqual(t, received.MessageID, "1700000000.000100", "Message ID")
+}
+
+func TestNewDiscordInteraction(t *testing.T) {
+	// Arrange
+	event := &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			Type:      discordgo.InteractionMessageComponent,
+			ChannelID: "channel123",
+			Member:    &discordgo.Member{User: &discordgo.User{ID: "user123"}},
+			Message:   &discordgo.Message{ID: "message123"},
+			Data: discordgo.MessageComponentInteractionData{
+				CustomID:      "approve:deploy-42",
+				ComponentType: discordgo.ButtonComponent,
+			},
+		},
+	}
+
+	// Act
+	interaction := (&Bot{}).newDiscordInteraction(event)
+
+	// Assert
+	assertEqual(t, interaction.ActionID, "approve", "Action ID")
+	assertEqual(t, interaction.Value, "deploy-42", "Value")
+	assertEqual(t, interaction.UserID, "user123", "User ID")
+	assertEqual(t, interaction.ChannelID, "channel123", "Channel ID")
+	assertEqual(t, interaction.MessageID, "message123", "Message ID")
+}
+
+func newDiscordInteractionServer(t *testing.T) (*Bot, func() []int) {
+	var mu sync.Mutex
+	var responses []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response struct {
+			Type int `json:"type"`
+		}
+		json.NewDecoder(r.Body).Decode(&response)
+		mu.Lock()
+		responses = append(responses, response.Type)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	bot, err := NewDiscordBot(WithDiscordToken("test_token"), WithAPIURL(server.URL))
+	assertNoError(t, err, "NewDiscordBot should not fail")
+	return bot, func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]int(nil), responses...)
+	}
+}
+
+func newDiscordComponentEvent(customID string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:        "interaction123",
+		Token:     "token",
+		Type:      discordgo.InteractionMessageComponent,
+		ChannelID: "channel123",
+		Data:      discordgo.MessageComponentInteractionData{CustomID: customID},
+	}}
+}
+
+func TestHandleDiscordInteractionCreate_SlowHandlerIsDeferred(t *testing.T) {
+	// Arrange
+	bot, responses := newDiscordInteractionServer(t)
+	deadline := make(chan time.Time)
+	bot.interactionAckAfter = func(time.Duration) <-chan time.Time { return deadline }
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	bot.OnAction("deploy:prod", func(bot *Bot, interaction *Interaction) {
+		<-release
+		close(finished)
+	})
+
+	// Act
+	go func() {
+		deadline <- time.Now()
+	}()
+	bot.handleDiscordInteractionCreate(bot.DiscordSession, newDiscordComponentEvent("deploy:prod"))
+	acked := responses()
+	close(release)
+	<-finished
+
+	// Assert
+	assertEqual(t, len(acked), 1, "Deferred answer should be sent while the handler runs")
+	assertEqual(t, acked[0], int(discordgo.InteractionResponseDeferredMessageUpdate), "Response type")
+}
+
+func TestHandleDiscordInteractionCreate_OpenFormSkipsDeferredAnswer(t *testing.T) {
+	// Arrange
+	bot, responses := newDiscordInteractionServer(t)
+	var formErr error
+	bot.OnAction("incident", func(bot *Bot, interaction *Interaction) {
+		formErr = bot.OpenForm(interaction, &Form{ID: "incident", Title: "Incident"})
+	})
+
+	// Act
+	bot.handleDiscordInteractionCreate(bot.DiscordSession, newDiscordComponentEvent("incident"))
+
+	// Assert
+	assertNoError(t, formErr, "OpenForm should answer the interaction")
+	assertEqual(t, len(responses()), 1, "Only the modal should be sent")
+	assertEqual(t, responses()[0], int(discordgo.InteractionResponseModal), "Response type")
+}
@@ -0,0 +1,103 @@
+package botbooter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultShutdownTimeout = 10 * time.Second
+
+type handlerTracker struct {
+	mu       sync.Mutex
+	active   int
+	draining bool
+	idle     chan struct{}
+}
+
+func (t *handlerTracker) begin() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.draining {
+		return false
+	}
+	t.active++
+	return true
+}
+
+func (t *handlerTracker) end() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.active--
+	if t.active == 0 && t.idle != nil {
+		close(t.idle)
+		t.idle = nil
+	}
+}
+
+// drain stops new handlers from starting and returns a channel that is closed once
+// the running ones have finished.
+func (t *handlerTracker) drain() <-chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.draining = true
+	idle := make(chan struct{})
+	if t.active == 0 {
+		close(idle)
+	} else {
+		t.idle = idle
+	}
+	return idle
+}
+
+func (t *handlerTracker) resume() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.draining = false
+}
+
+// Run connects the bot and blocks until ctx is cancelled, then drains running
+// handlers for up to ShutdownTimeout and disconnects.
+func (b *Bot) Run(ctx context.Context) error {
+	if b.BotType != SlackBotType && b.BotType != DiscordBotType {
+		return fmt.Errorf("unknown bot type")
+	}
+
+	supervised := make(chan error, 1)
+	go func() {
+		supervised <- b.supervise(ctx)
+	}()
+
+	for {
+		select {
+		case err := <-supervised:
+			if err != nil {
+				return err
+			}
+			// Discord returns as soon as the gateway is open; keep serving until cancelled.
+			supervised = nil
+		case <-ctx.Done():
+			return b.shutdown()
+		}
+	}
+}
+
+func (b *Bot) shutdown() error {
+	timeout := b.ShutdownTimeout
+	if timeout == 0 {
+		timeout = defaultShutdownTimeout
+	}
+
+	select {
+	case <-b.handlers.drain():
+	case <-time.After(timeout):
+		b.logger().Warn("Timed out waiting for handlers to finish", "timeout", timeout)
+	}
+
+	return b.Disconnect()
+}
@@ -0,0 +1,134 @@
+package botbooter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandlerTracker_Drain(t *testing.T) {
+	// Arrange
+	tracker := &handlerTracker{}
+	assertTrue(t, tracker.begin(), "Handlers start before draining")
+
+	// Act
+	idle := tracker.drain()
+
+	// Assert
+	assertFalse(t, tracker.begin(), "New handlers are rejected while draining")
+	select {
+	case <-idle:
+		t.Fatal("Drain should wait for the running handler")
+	default:
+	}
+	tracker.end()
+	select {
+	case <-idle:
+	case <-time.After(time.Second):
+		t.Fatal("Drain should finish once the running handler ends")
+	}
+	tracker.resume()
+	assertTrue(t, tracker.begin(), "Handlers start again after resume")
+}
+
+func TestBot_Shutdown_WaitsForHandlers(t *testing.T) {
+	// Arrange
+	bot := InitAsSlackBot("xapp-test", "xoxb-test")
+	started := make(chan struct{})
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	bot.AddHandler(Command{
+		Pattern: "^slow",
+		Handler: func(bot *Bot, message *Message) {
+			close(started)
+			<-release
+			close(finished)
+		},
+	})
+	go bot.dispatchIncoming(&Message{Content: "slow"})
+	<-started
+
+	// Act
+	done := make(chan error, 1)
+	go func() {
+		done <- bot.shutdown()
+	}()
+	time.Sleep(50 * time.Millisecond)
+	bot.dispatchIncoming(&Message{Content: "slow again"})
+	close(release)
+
+	// Assert
+	select {
+	case err := <-done:
+		assertNoError(t, err, "Shutdown should not fail")
+	case <-time.After(time.Second):
+		t.Fatal("Shutdown should return once the handler finishes")
+	}
+	<-finished
+}
+
+func TestBot_Shutdown_Timeout(t *testing.T) {
+	// Arrange
+	bot := InitAsSlackBot("xapp-test", "xoxb-test")
+	bot.ShutdownTimeout = 20 * time.Millisecond
+	bot.handlers.begin()
+	defer bot.handlers.end()
+
+	// Act
+	start := time.Now()
+	err := bot.shutdown()
+
+	// Assert
+	assertNoError(t, err, "Shutdown should not fail")
+	assertTrue(t, time.Since(start) < time.Second, "Shutdown should give up after the timeout")
+}
+
+func TestBot_Run_ConnectError(t *testing.T) {
+	t.Run("UnknownBotType", func(t *testing.T) {
+		// Arrange
+		bot := &Bot{BotType: BotType(999)}
+
+		// Act
+		err := bot.Run(context.Background())
+
+		// Assert
+		assertError(t, err, "Run should reject an unknown bot type")
+	})
+
+	t.Run("InvalidAuth", func(t *testing.T) {
+		// Arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"ok":false,"error":"invalid_auth"}`))
+		}))
+		defer server.Close()
+
+		bot, err := NewSlackBot(WithSlackTokens("xapp-test", "xoxb-revoked"), WithAPIURL(server.URL+"/api/"))
+		assertNoError(t, err, "NewSlackBot should not fail")
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		// Act
+		err = bot.Run(ctx)
+
+		// Assert
+		assertError(t, err, "Run should return a rejected token")
+		assertNil(t, ctx.Err(), "Run should return before the context is cancelled")
+	})
+}
+
+func TestDisconnectSlack_CancelsRun(t *testing.T) {
+	// Arrange
+	bot := InitAsSlackBot("xapp-test", "xoxb-test")
+	ctx, cancel := context.WithCancel(context.Background())
+	bot.slackCancel = cancel
+
+	// Act
+	err := bot.disconnectSlack()
+
+	// Assert
+	assertNoError(t, err, "Disconnect Slack should not fail")
+	assertError(t, ctx.Err(), "Socket mode context should be cancelled")
+	assertTrue(t, bot.slackCancel == nil, "Cancel func should be cleared")
+}
@@ -0,0 +1,150 @@
+package botbooter
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+type LogLevel int
+
+const (
+	DebugLevel LogLevel = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case DebugLevel:
+		return "DEBUG"
+	case InfoLevel:
+		return "INFO"
+	case WarnLevel:
+		return "WARN"
+	case ErrorLevel:
+		return "ERROR"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Logger receives structured log entries as a message plus alternating key/value pairs.
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Info(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+	Error(msg string, keysAndValues ...interface{})
+}
+
+type stdLogger struct {
+	logger *log.Logger
+	level  LogLevel
+}
+
+// NewStdLogger writes entries at or above level to a standard library logger, or to
+// the default one when l is nil.
+func NewStdLogger(l *log.Logger, level LogLevel) Logger {
+	if l == nil {
+		l = log.Default()
+	}
+	return &stdLogger{logger: l, level: level}
+}
+
+func (l *stdLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.log(DebugLevel, msg, keysAndValues)
+}
+
+func (l *stdLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.log(InfoLevel, msg, keysAndValues)
+}
+
+func (l *stdLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.log(WarnLevel, msg, keysAndValues)
+}
+
+func (l *stdLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.log(ErrorLevel, msg, keysAndValues)
+}
+
+func (l *stdLogger) log(level LogLevel, msg string, keysAndValues []interface{}) {
+	if level < l.level {
+		return
+	}
+	l.logger.Println(formatLogEntry(level, msg, keysAndValues))
+}
+
+func formatLogEntry(level LogLevel, msg string, keysAndValues []interface{}) string {
+	var sb strings.Builder
+	sb.WriteString(level.String())
+	sb.WriteString(" ")
+	sb.WriteString(msg)
+
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key := fmt.Sprint(keysAndValues[i])
+		var value interface{} = "(MISSING)"
+		if i+1 < len(keysAndValues) {
+			value = keysAndValues[i+1]
+		}
+
+		formatted := fmt.Sprint(value)
+		if strings.ContainsAny(formatted, " \t\n\"=") {
+			formatted = fmt.Sprintf("%q", formatted)
+		}
+		fmt.Fprintf(&sb, " %s=%s", key, formatted)
+	}
+	return sb.String()
+}
+
+// SugaredLogger matches the key/value methods of zap's SugaredLogger.
+type SugaredLogger interface {
+	Debugw(msg string, keysAndValues ...interface{})
+	Infow(msg string, keysAndValues ...interface{})
+	Warnw(msg string, keysAndValues ...interface{})
+	Errorw(msg string, keysAndValues ...interface{})
+}
+
+type sugaredLogger struct {
+	logger SugaredLogger
+}
+
+func NewSugaredLogger(l SugaredLogger) Logger {
+	return sugaredLogger{logger: l}
+}
+
+func (l sugaredLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.logger.Debugw(msg, keysAndValues...)
+}
+
+func (l sugaredLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.logger.Infow(msg, keysAndValues...)
+}
+
+func (l sugaredLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.logger.Warnw(msg, keysAndValues...)
+}
+
+func (l sugaredLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.logger.Errorw(msg, keysAndValues...)
+}
+
+type nopLogger struct{}
+
+func NewNopLogger() Logger {
+	return nopLogger{}
+}
+
+func (nopLogger) Debug(string, ...interface{}) {}
+func (nopLogger) Info(string, ...interface{})  {}
+func (nopLogger) Warn(string, ...interface{})  {}
+func (nopLogger) Error(string, ...interface{}) {}
+
+var defaultLogger = NewStdLogger(nil, InfoLevel)
+
+func (b *Bot) logger() Logger {
+	if b.Logger != nil {
+		return b.Logger
+	}
+	return defaultLogger
+}
@@ -0,0 +1,95 @@
+package botbooter
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack/slackevents"
+)
+
+func TestStdLogger_LevelsAndFields(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	logger := NewStdLogger(log.New(&buf, "", 0), InfoLevel)
+
+	// Act
+	logger.Debug("hidden")
+	logger.Info("Command matched", "pattern", "^deploy", "user", "U123")
+	logger.Error("Send failed", "error", "rate limited", "orphan")
+
+	// Assert
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assertEqual(t, len(lines), 2, "Debug entries are filtered out")
+	assertEqual(t, lines[0], "INFO Command matched pattern=^deploy user=U123", "Info entry")
+	assertEqual(t, lines[1], `ERROR Send failed error="rate limited" orphan=(MISSING)`, "Error entry with quoting")
+}
+
+type recordingSugaredLogger struct {
+	entries []string
+}
+
+func (l *recordingSugaredLogger) record(level, msg string, keysAndValues []interface{}) {
+	l.entries = append(l.entries, fmt.Sprint(level, " ", msg, " ", keysAndValues))
+}
+
+func (l *recordingSugaredLogger) Debugw(msg string, kv ...interface{}) { l.record("debug", msg, kv) }
+func (l *recordingSugaredLogger) Infow(msg string, kv ...interface{})  { l.record("info", msg, kv) }
+func (l *recordingSugaredLogger) Warnw(msg string, kv ...interface{})  { l.record("warn", msg, kv) }
+func (l *recordingSugaredLogger) Errorw(msg string, kv ...interface{}) { l.record("error", msg, kv) }
+
+func TestSugaredLogger(t *testing.T) {
+	// Arrange
+	sugared := &recordingSugaredLogger{}
+	logger := NewSugaredLogger(sugared)
+
+	// Act
+	logger.Debug("a", "k", 1)
+	logger.Info("b")
+	logger.Warn("c")
+	logger.Error("d", "error", "boom")
+
+	// Assert
+	assertEqual(t, len(sugared.entries), 4, "Number of entries")
+	assertEqual(t, sugared.entries[0], "debug a [k 1]", "Debug entry")
+	assertEqual(t, sugared.entries[3], "error d [error boom]", "Error entry")
+}
+
+func TestBot_Logger_DispatchDecisions(t *testing.T) {
+	// Arrange
+	sugared := &recordingSugaredLogger{}
+	bot := InitAsSlackBot("xapp-test", "xoxb-test")
+	bot.Logger = NewSugaredLogger(sugared)
+	bot.AddHandler(Command{
+		Pattern: "^deploy",
+		Handler: func(bot *Bot, message *Message) {},
+	})
+
+	// Act
+	bot.handleSlackEventsApi(slackevents.EventsAPIEvent{
+		InnerEvent: slackevents.EventsAPIInnerEvent{
+			Type: "message",
+			Data: &slackevents.MessageEvent{Text: "deploy", BotID: "B123", Channel: "C456"},
+		},
+	})
+	bot.handleSlackEventsApi(slackMessageEvent("deploy api", "channel"))
+	bot.handleSlackEventsApi(slackMessageEvent("hello", "channel"))
+
+	// Assert
+	assertEqual(t, len(sugared.entries), 3, "One debug entry per decision")
+	assertEqual(t, sugared.entries[0], "debug Slack event ignored, sent by a bot [type message]", "Bot message entry")
+	assertEqual(t, sugared.entries[1], "debug Command matched [pattern ^deploy channel C456 user U123]", "Matched command entry")
+	assertEqual(t, sugared.entries[2], "debug No command matched [channel C456 user U123]", "Unmatched entry")
+}
+
+func TestBot_Logger_Default(t *testing.T) {
+	// Arrange
+	bot := &Bot{}
+
+	// Act & Assert
+	assertTrue(t, bot.logger() == defaultLogger, "Default logger")
+	bot.Logger = NewNopLogger()
+	assertTrue(t, bot.logger() == bot.Logger, "Configured logger")
+}
@@ -0,0 +1,251 @@
+package botbooter
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/slack-go/slack"
+)
+
+const defaultLookupCacheTTL = 5 * time.Minute
+
+type ChannelType string
+
+const (
+	PublicChannel   ChannelType = "public"
+	PrivateChannel  ChannelType = "private"
+	DirectChannel   ChannelType = "direct"
+	GroupChannel    ChannelType = "group"
+	ThreadChannel   ChannelType = "thread"
+	VoiceChannel    ChannelType = "voice"
+	CategoryChannel ChannelType = "category"
+)
+
+type User struct {
+	ID          string
+	Name        string
+	DisplayName string
+	RealName    string
+	Email       string
+	Timezone    string
+	IsBot       bool
+	SlackData   *slack.User
+	DiscordData *discordgo.User
+}
+
+type Channel struct {
+	ID          string
+	Name        string
+	Type        ChannelType
+	Topic       string
+	SlackData   *slack.Channel
+	DiscordData *discordgo.Channel
+}
+
+type lookupCacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+type lookupCache struct {
+	mu      sync.Mutex
+	entries map[string]lookupCacheEntry
+	now     func() time.Time
+}
+
+func (c *lookupCache) get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.clock().Before(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (c *lookupCache) put(key string, value interface{}, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = map[string]lookupCacheEntry{}
+	}
+	c.entries[key] = lookupCacheEntry{value: value, expires: c.clock().Add(ttl)}
+}
+
+func (c *lookupCache) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+func (b *Bot) lookupCacheTTL() time.Duration {
+	if b.LookupCacheTTL == 0 {
+		return defaultLookupCacheTTL
+	}
+	return b.LookupCacheTTL
+}
+
+func (b *Bot) GetUser(userID string) (*User, error) {
+	if cached, ok := b.lookups.get("user/" + userID); ok {
+		return cached.(*User), nil
+	}
+
+	var user *User
+	switch b.BotType {
+	case SlackBotType:
+		slackUser, err := b.SlackClient.GetUserInfo(userID)
+		if err != nil {
+			return nil, err
+		}
+		user = slackUserProfile(slackUser)
+	case DiscordBotType:
+		discordUser, err := b.DiscordSession.User(userID)
+		if err != nil {
+			return nil, err
+		}
+		user = discordUserProfile(discordUser, discordNickname(b.DiscordSession, userID))
+	default:
+		return nil, fmt.Errorf("unknown bot type")
+	}
+
+	b.lookups.put("user/"+userID, user, b.lookupCacheTTL())
+	return user, nil
+}
+
+func (b *Bot) GetChannel(channelID string) (*Channel, error) {
+	if cached, ok := b.lookups.get("channel/" + channelID); ok {
+		return cached.(*Channel), nil
+	}
+
+	var channel *Channel
+	switch b.BotType {
+	case SlackBotType:
+		slackChannel, err := b.SlackClient.GetConversationInfo(&slack.GetConversationInfoInput{ChannelID: channelID})
+		if err != nil {
+			return nil, err
+		}
+		channel = slackChannelInfo(slackChannel)
+	case DiscordBotType:
+		discordChannel, err := b.DiscordSession.State.Channel(channelID)
+		if err != nil {
+			discordChannel, err = b.DiscordSession.Channel(channelID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		channel = discordChannelInfo(discordChannel)
+	default:
+		return nil, fmt.Errorf("unknown bot type")
+	}
+
+	b.lookups.put("channel/"+channelID, channel, b.lookupCacheTTL())
+	return channel, nil
+}
+
+func slackUserProfile(u *slack.User) *User {
+	return &User{
+		ID:          u.ID,
+		Name:        u.Name,
+		DisplayName: firstNonEmpty(u.Profile.DisplayName, u.RealName, u.Name),
+		RealName:    firstNonEmpty(u.RealName, u.Profile.RealName),
+		Email:       u.Profile.Email,
+		Timezone:    u.TZ,
+		IsBot:       u.IsBot,
+		SlackData:   u,
+	}
+}
+
+func discordUserProfile(u *discordgo.User, nickname string) *User {
+	// Discord does not expose real names or timezones, and email needs the OAuth2 email scope.
+	return &User{
+		ID:          u.ID,
+		Name:        u.Username,
+		DisplayName: firstNonEmpty(nickname, u.Username),
+		Email:       u.Email,
+		IsBot:       u.Bot,
+		DiscordData: u,
+	}
+}
+
+func discordNickname(s *discordgo.Session, userID string) string {
+	if s == nil || s.State == nil {
+		return ""
+	}
+
+	s.State.RLock()
+	guilds := append([]*discordgo.Guild(nil), s.State.Guilds...)
+	s.State.RUnlock()
+
+	for _, guild := range guilds {
+		if member, err := s.State.Member(guild.ID, userID); err == nil && member.Nick != "" {
+			return member.Nick
+		}
+	}
+	return ""
+}
+
+func slackChannelInfo(c *slack.Channel) *Channel {
+	channelType := PublicChannel
+	switch {
+	case c.IsIM:
+		channelType = DirectChannel
+	case c.IsMpIM:
+		channelType = GroupChannel
+	case c.IsPrivate:
+		channelType = PrivateChannel
+	}
+
+	return &Channel{
+		ID:        c.ID,
+		Name:      c.Name,
+		Type:      channelType,
+		Topic:     c.Topic.Value,
+		SlackData: c,
+	}
+}
+
+func discordChannelInfo(c *discordgo.Channel) *Channel {
+	channelType := PublicChannel
+	switch c.Type {
+	case discordgo.ChannelTypeDM:
+		channelType = DirectChannel
+	case discordgo.ChannelTypeGroupDM:
+		channelType = GroupChannel
+	case discordgo.ChannelTypeGuildPublicThread, discordgo.ChannelTypeGuildPrivateThread, discordgo.ChannelTypeGuildNewsThread:
+		channelType = ThreadChannel
+	case discordgo.ChannelTypeGuildVoice, discordgo.ChannelTypeGuildStageVoice:
+		channelType = VoiceChannel
+	case discordgo.ChannelTypeGuildCategory:
+		channelType = CategoryChannel
+	}
+
+	return &Channel{
+		ID:          c.ID,
+		Name:        c.Name,
+		Type:        channelType,
+		Topic:       c.Topic,
+		DiscordData: c,
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,139 @@
+package botbooter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/slack-go/slack"
+)
+
+func TestLookupCache_Expiry(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache := &lookupCache{now: func() time.Time { return now }}
+	cache.put("user/U123", "alice", time.Minute)
+
+	// Act
+	_, freshOK := cache.get("user/U123")
+	now = now.Add(time.Minute)
+	_, expiredOK := cache.get("user/U123")
+
+	// Assert
+	assertTrue(t, freshOK, "Entry should be cached before the TTL")
+	assertFalse(t, expiredOK, "Entry should expire after the TTL")
+}
+
+func TestLookupCache_Disabled(t *testing.T) {
+	// Arrange
+	cache := &lookupCache{}
+
+	// Act
+	cache.put("user/U123", "alice", -1)
+	_, ok := cache.get("user/U123")
+
+	// Assert
+	assertFalse(t, ok, "Negative TTL should disable caching")
+}
+
+func TestBot_GetUser_Cached(t *testing.T) {
+	// Arrange
+	bot := InitAsSlackBot("xapp-test", "xoxb-test")
+	bot.lookups.put("user/U123", &User{ID: "U123", DisplayName: "alice"}, time.Minute)
+
+	// Act
+	user, err := bot.GetUser("U123")
+
+	// Assert
+	assertNoError(t, err, "GetUser should use the cache")
+	assertEqual(t, user.DisplayName, "alice", "Display name")
+}
+
+func TestBot_GetChannel_DiscordState(t *testing.T) {
+	// Arrange
+	bot := InitAsDiscordBot("test_token")
+	bot.DiscordSession.State.GuildAdd(&discordgo.Guild{ID: "guild123"})
+	bot.DiscordSession.State.ChannelAdd(&discordgo.Channel{
+		ID:      "channel123",
+		GuildID: "guild123",
+		Name:    "general",
+		Topic:   "Anything goes",
+		Type:    discordgo.ChannelTypeGuildText,
+	})
+
+	// Act
+	channel, err := bot.GetChannel("channel123")
+
+	// Assert
+	assertNoError(t, err, "GetChannel should read from state")
+	assertEqual(t, channel.Name, "general", "Channel name")
+	assertEqual(t, channel.Type, PublicChannel, "Channel type")
+	assertEqual(t, channel.Topic, "Anything goes", "Channel topic")
+}
+
+func TestBot_GetUser_UnknownBotType(t *testing.T) {
+	// Arrange
+	bot := &Bot{BotType: BotType(999)}
+
+	// Act
+	_, userErr := bot.GetUser("U123")
+	_, channelErr := bot.GetChannel("C123")
+
+	// Assert
+	assertError(t, userErr, "GetUser with unknown bot type")
+	assertError(t, channelErr, "GetChannel with unknown bot type")
+}
+
+func TestSlackUserProfile(t *testing.T) {
+	// Arrange
+	slackUser := &slack.User{
+		ID:       "U123",
+		Name:     "alice",
+		RealName: "Alice Liddell",
+		TZ:       "Europe/London",
+		Profile:  slack.UserProfile{DisplayName: "ali", Email: "alice@example.com"},
+	}
+
+	// Act
+	user := slackUserProfile(slackUser)
+
+	// Assert
+	assertEqual(t, user.DisplayName, "ali", "Display name")
+	assertEqual(t, user.RealName, "Alice Liddell", "Real name")
+	assertEqual(t, user.Email, "alice@example.com", "Email")
+	assertEqual(t, user.Timezone, "Europe/London", "Timezone")
+	assertFalse(t, user.IsBot, "Is bot")
+}
+
+func TestSlackChannelInfo_Types(t *testing.T) {
+	// Arrange
+	im := &slack.Channel{}
+	im.IsIM = true
+	private := &slack.Channel{}
+	private.IsPrivate = true
+	private.Name = "secret"
+
+	// Act & Assert
+	assertEqual(t, slackChannelInfo(im).Type, DirectChannel, "IM channel type")
+	assertEqual(t, slackChannelInfo(private).Type, PrivateChannel, "Private channel type")
+	assertEqual(t, slackChannelInfo(private).Name, "secret", "Channel name")
+}
+
+func TestDiscordUserProfile_Nickname(t *testing.T) {
+	// Arrange
+	bot := InitAsDiscordBot("test_token")
+	bot.DiscordSession.State.GuildAdd(&discordgo.Guild{ID: "guild123"})
+	bot.DiscordSession.State.MemberAdd(&discordgo.Member{
+		GuildID: "guild123",
+		Nick:    "ali",
+		User:    &discordgo.User{ID: "user123", Username: "alice"},
+	})
+
+	// Act
+	user := discordUserProfile(&discordgo.User{ID: "user123", Username: "alice", Bot: true}, discordNickname(bot.DiscordSession, "user123"))
+
+	// Assert
+	assertEqual(t, user.Name, "alice", "Username")
+	assertEqual(t, user.DisplayName, "ali", "Guild nickname")
+	assertTrue(t, user.IsBot, "Is bot")
+}
@@ -0,0 +1,64 @@
+package botbooter
+
+import (
+	"regexp"
+	"strings"
+)
+
+var leadingMentionPattern = regexp.MustCompile(`^\s*<@!?([A-Za-z0-9]+)(?:\|[^>]*)?>[\s:,]*`)
+
+func stripLeadingMention(content, userID string) (string, bool) {
+	match := leadingMentionPattern.FindStringSubmatch(content)
+	if match == nil || (userID != "" && match[1] != userID) {
+		return content, false
+	}
+	return content[len(match[0]):], true
+}
+
+func mentionsUser(content, userID string) bool {
+	if userID == "" {
+		return false
+	}
+	return strings.Contains(content, "<@"+userID+">") ||
+		strings.Contains(content, "<@!"+userID+">") ||
+		strings.Contains(content, "<@"+userID+"|")
+}
+
+func (b *Bot) selfUserID() string {
+	switch b.BotType {
+	case SlackBotType:
+		return b.slackBotUserID
+	case DiscordBotType:
+		if b.DiscordSession != nil && b.DiscordSession.State != nil && b.DiscordSession.State.User != nil {
+			return b.DiscordSession.State.User.ID
+		}
+	}
+	return ""
+}
+
+func (b *Bot) dispatchIncoming(message *Message) {
+	if !b.handlers.begin() {
+		b.logger().Debug("Message ignored, bot is shutting down", "channel", message.ChannelID)
+		return
+	}
+	defer b.handlers.end()
+
+	b.metrics.incReceived(b.platform())
+	botUserID := b.selfUserID()
+	message.Entities = parseEntities(message.Content)
+
+	if mentionsUser(message.Content, botUserID) {
+		message.MentionsBot = true
+	}
+	// Stripping changes what Command patterns see, so it is part of the mention-only opt-in.
+	if b.MentionOnly && message.MentionsBot {
+		message.Content, _ = stripLeadingMention(message.Content, botUserID)
+	}
+
+	if b.MentionOnly && !message.IsDirect && !message.MentionsBot {
+		b.logger().Debug("Message ignored, bot not mentioned", "channel", message.ChannelID, "user", message.UserID)
+		return
+	}
+
+	b.dispatchMessage(message)
+}
@@ -0,0 +1,180 @@
+package botbooter
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/slack-go/slack/slackevents"
+)
+
+func TestStripLeadingMention(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		userID      string
+		wantContent string
+		wantOK      bool
+	}{
+		{"slack mention", "<@U123> deploy api", "U123", "deploy api", true},
+		{"slack mention with label", "<@U123|bot>: deploy api", "U123", "deploy api", true},
+		{"discord nickname mention", "<@!123>, deploy api", "123", "deploy api", true},
+		{"other user", "<@U999> deploy api", "U123", "<@U999> deploy api", false},
+		{"any user", "<@U999> deploy api", "", "deploy api", true},
+		{"mention not leading", "deploy <@U123>", "U123", "deploy <@U123>", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, ok := stripLeadingMention(tt.content, tt.userID)
+			assertEqual(t, content, tt.wantContent, "Stripped content")
+			assertEqual(t, ok, tt.wantOK, "Mention stripped")
+		})
+	}
+}
+
+func TestMentionsUser(t *testing.T) {
+	assertTrue(t, mentionsUser("hey <@U123> help", "U123"), "Slack mention")
+	assertTrue(t, mentionsUser("hey <@!123> help", "123"), "Discord nickname mention")
+	assertFalse(t, mentionsUser("hey <@U1234> help", "U123"), "Different user")
+	assertFalse(t, mentionsUser("hey <@U123> help", ""), "Unknown bot user")
+}
+
+func newSlackMentionTestBot(mentionOnly bool) (*Bot, *[]*Message) {
+	bot := InitAsSlackBot("xapp-test", "xoxb-test")
+	bot.MentionOnly = mentionOnly
+	bot.slackBotUserID = "UBOT"
+	var received []*Message
+	bot.AddHandler(Command{
+		Pattern: "^deploy",
+		Handler: func(bot *Bot, message *Message) {
+			received = append(received, message)
+		},
+	})
+	return bot, &received
+}
+
+func slackMessageEvent(text, channelType string) slackevents.EventsAPIEvent {
+	return slackevents.EventsAPIEvent{
+		InnerEvent: slackevents.EventsAPIInnerEvent{
+			Data: &slackevents.MessageEvent{
+				Text:        text,
+				User:        "U123",
+				Channel:     "C456",
+				ChannelType: channelType,
+			},
+		},
+	}
+}
+
+func TestHandleSlackEventsApi_Mentions(t *testing.T) {
+	t.Run("KeepsLeadingMentionWithoutMentionOnly", func(t *testing.T) {
+		// Arrange
+		bot, received := newSlackMentionTestBot(false)
+		var mentioned []*Message
+		bot.AddHandler(Command{
+			Pattern: "^<@UBOT> deploy",
+			Handler: func(bot *Bot, message *Message) {
+				mentioned = append(mentioned, message)
+			},
+		})
+
+		// Act
+		bot.handleSlackEventsApi(slackMessageEvent("<@UBOT> deploy api", "channel"))
+
+		// Assert
+		assertEqual(t, len(*received), 0, "Patterns should see the mention")
+		assertEqual(t, len(mentioned), 1, "Pattern with mention should be dispatched")
+		assertEqual(t, mentioned[0].Content, "<@UBOT> deploy api", "Content with mention")
+		assertTrue(t, mentioned[0].MentionsBot, "MentionsBot flag")
+	})
+
+	t.Run("MentionOnlyIgnoresChannelMessages", func(t *testing.T) {
+		// Arrange
+		bot, received := newSlackMentionTestBot(true)
+
+		// Act
+		bot.handleSlackEventsApi(slackMessageEvent("deploy api", "channel"))
+		bot.handleSlackEventsApi(slackMessageEvent("<@UBOT> deploy api", "channel"))
+
+		// Assert
+		assertEqual(t, len(*received), 0, "Channel messages should not be dispatched in mention-only mode")
+	})
+
+	t.Run("MentionOnlyDispatchesDirectMessages", func(t *testing.T) {
+		// Arrange
+		bot, received := newSlackMentionTestBot(true)
+
+		// Act
+		bot.handleSlackEventsApi(slackMessageEvent("deploy api", "im"))
+
+		// Assert
+		assertEqual(t, len(*received), 1, "DMs should be dispatched in mention-only mode")
+	})
+
+	t.Run("MentionOnlyDispatchesAppMentions", func(t *testing.T) {
+		// Arrange
+		bot, received := newSlackMentionTestBot(true)
+		event := slackevents.EventsAPIEvent{
+			InnerEvent: slackevents.EventsAPIInnerEvent{
+				Data: &slackevents.AppMentionEvent{
+					Text:      "<@UBOT> deploy api",
+					User:      "U123",
+					Channel:   "C456",
+					TimeStamp: "1700000000.000100",
+				},
+			},
+		}
+
+		// Act
+		bot.handleSlackEventsApi(event)
+
+		// Assert
+		assertEqual(t, len(*received), 1, "App mentions should be dispatched in mention-only mode")
+		assertEqual(t, (*received)[0].Content, "deploy api", "Content without mention")
+		assertEqual(t, (*received)[0].MessageID, "1700000000.000100", "Message ID")
+		assertTrue(t, (*received)[0].MentionsBot, "MentionsBot flag")
+	})
+}
+
+func TestHandleDiscordMessageCreate_Mentions(t *testing.T) {
+	// Arrange
+	bot := InitAsDiscordBot("test_token")
+	bot.MentionOnly = true
+	bot.DiscordSession.State.User = &discordgo.User{ID: "bot123"}
+	var received []*Message
+	bot.AddHandler(Command{
+		Pattern: "^deploy",
+		Handler: func(bot *Bot, message *Message) {
+			received = append(received, message)
+		},
+	})
+
+	// Act
+	bot.handleDiscordMessageCreate(bot.DiscordSession, &discordgo.MessageCreate{Message: &discordgo.Message{
+		ID:        "message1",
+		ChannelID: "channel123",
+		GuildID:   "guild123",
+		Content:   "deploy api",
+		Author:    &discordgo.User{ID: "user123"},
+	}})
+	bot.handleDiscordMessageCreate(bot.DiscordSession, &discordgo.MessageCreate{Message: &discordgo.Message{
+		ID:        "message2",
+		ChannelID: "channel123",
+		GuildID:   "guild123",
+		Content:   "<@!bot123> deploy api",
+		Author:    &discordgo.User{ID: "user123"},
+		Mentions:  []*discordgo.User{{ID: "bot123"}},
+	}})
+	bot.handleDiscordMessageCreate(bot.DiscordSession, &discordgo.MessageCreate{Message: &discordgo.Message{
+		ID:        "message3",
+		ChannelID: "dm123",
+		Content:   "deploy web",
+		Author:    &discordgo.User{ID: "user123"},
+	}})
+
+	// Assert
+	assertEqual(t, len(received), 2, "Only mentions and DMs should be dispatched")
+	assertEqual(t, received[0].Content, "deploy api", "Content without mention")
+	assertTrue(t, received[0].MentionsBot, "MentionsBot flag")
+	assertTrue(t, received[1].IsDirect, "IsDirect flag")
+}
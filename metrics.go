@@ -0,0 +1,161 @@
+package botbooter
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var handlerDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func (h *histogram) observe(seconds float64) {
+	if h.counts == nil {
+		h.counts = make([]uint64, len(handlerDurationBuckets))
+	}
+	for i, bound := range handlerDurationBuckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+type metrics struct {
+	mu               sync.Mutex
+	messagesReceived map[string]uint64
+	commandsExecuted map[string]uint64
+	handlerDuration  map[string]*histogram
+	messagesSent     map[string]uint64
+	sendFailures     map[string]uint64
+	messagesDropped  uint64
+}
+
+func (m *metrics) incReceived(platform string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.messagesReceived == nil {
+		m.messagesReceived = map[string]uint64{}
+	}
+	m.messagesReceived[platform]++
+}
+
+func (m *metrics) observeCommand(command string, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.commandsExecuted == nil {
+		m.commandsExecuted = map[string]uint64{}
+		m.handlerDuration = map[string]*histogram{}
+	}
+	m.commandsExecuted[command]++
+	h, ok := m.handlerDuration[command]
+	if !ok {
+		h = &histogram{}
+		m.handlerDuration[command] = h
+	}
+	h.observe(duration.Seconds())
+}
+
+func (m *metrics) observeSend(platform string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.messagesSent == nil {
+		m.messagesSent = map[string]uint64{}
+		m.sendFailures = map[string]uint64{}
+	}
+	if err != nil {
+		m.sendFailures[platform]++
+		return
+	}
+	m.messagesSent[platform]++
+}
+
+func (m *metrics) incDropped() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messagesDropped++
+}
+
+func (b *Bot) platform() string {
+	switch b.BotType {
+	case SlackBotType:
+		return "slack"
+	case DiscordBotType:
+		return "discord"
+	default:
+		return "unknown"
+	}
+}
+
+// MetricsHandler serves the bot's counters and histograms in the Prometheus text format.
+func (b *Bot) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		b.metrics.writeTo(w)
+	})
+}
+
+func (m *metrics) writeTo(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	writeCounterFamily(w, "botbooter_messages_received_total", "Messages received from the platform.", "platform", m.messagesReceived)
+	writeCounterFamily(w, "botbooter_commands_executed_total", "Command handlers executed.", "command", m.commandsExecuted)
+
+	fmt.Fprintln(w, "# HELP botbooter_handler_duration_seconds Command handler latency.")
+	fmt.Fprintln(w, "# TYPE botbooter_handler_duration_seconds histogram")
+	for _, command := range sortedKeys(m.handlerDuration) {
+		h := m.handlerDuration[command]
+		label := fmt.Sprintf(`command="%s"`, escapeLabelValue(command))
+		for i, bound := range handlerDurationBuckets {
+			fmt.Fprintf(w, "botbooter_handler_duration_seconds_bucket{%s,le=\"%g\"} %d\n", label, bound, h.counts[i])
+		}
+		fmt.Fprintf(w, "botbooter_handler_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", label, h.count)
+		fmt.Fprintf(w, "botbooter_handler_duration_seconds_sum{%s} %g\n", label, h.sum)
+		fmt.Fprintf(w, "botbooter_handler_duration_seconds_count{%s} %d\n", label, h.count)
+	}
+
+	writeCounterFamily(w, "botbooter_messages_sent_total", "Messages sent successfully.", "platform", m.messagesSent)
+	writeCounterFamily(w, "botbooter_send_failures_total", "Failed send attempts.", "platform", m.sendFailures)
+
+	fmt.Fprintln(w, "# HELP botbooter_messages_dropped_total Messages dropped because the dispatcher queue was full.")
+	fmt.Fprintln(w, "# TYPE botbooter_messages_dropped_total counter")
+	fmt.Fprintf(w, "botbooter_messages_dropped_total %d\n", m.messagesDropped)
+}
+
+func writeCounterFamily(w io.Writer, name, help, label string, values map[string]uint64) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s counter\n", name)
+	for _, key := range sortedKeys(values) {
+		fmt.Fprintf(w, "%s{%s=\"%s\"} %d\n", name, label, escapeLabelValue(key), values[key])
+	}
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
@@ -0,0 +1,73 @@
+package botbooter
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBot_MetricsHandler(t *testing.T) {
+	// Arrange
+	bot := InitAsDiscordBot("test_token")
+	bot.AddHandler(Command{
+		Pattern: "^deploy",
+		Handler: func(bot *Bot, message *Message) {},
+	})
+	bot.dispatchIncoming(&Message{Content: "deploy api"})
+	bot.dispatchIncoming(&Message{Content: "hello"})
+	bot.metrics.observeSend("discord", nil)
+	bot.metrics.observeSend("discord", errMissingMessageID)
+	bot.metrics.incDropped()
+
+	// Act
+	recorder := httptest.NewRecorder()
+	bot.MetricsHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body := recorder.Body.String()
+
+	// Assert
+	assertEqual(t, recorder.Header().Get("Content-Type"), "text/plain; version=0.0.4; charset=utf-8", "Content type")
+	for _, line := range []string{
+		"# TYPE botbooter_messages_received_total counter",
+		`botbooter_messages_received_total{platform="discord"} 2`,
+		`botbooter_commands_executed_total{command="^deploy"} 1`,
+		"# TYPE botbooter_handler_duration_seconds histogram",
+		`botbooter_handler_duration_seconds_bucket{command="^deploy",le="+Inf"} 1`,
+		`botbooter_handler_duration_seconds_count{command="^deploy"} 1`,
+		`botbooter_messages_sent_total{platform="discord"} 1`,
+		`botbooter_send_failures_total{platform="discord"} 1`,
+		"botbooter_messages_dropped_total 1",
+	} {
+		assertTrue(t, strings.Contains(body, line+"\n"), "Metrics output should contain "+line)
+	}
+}
+
+func TestHistogram_Buckets(t *testing.T) {
+	// Arrange
+	h := &histogram{}
+
+	// Act
+	h.observe((20 * time.Millisecond).Seconds())
+	h.observe(3)
+
+	// Assert
+	assertEqual(t, h.counts[0], uint64(0), "5ms bucket")
+	assertEqual(t, h.counts[2], uint64(1), "25ms bucket")
+	assertEqual(t, h.counts[len(h.counts)-1], uint64(2), "10s bucket")
+	assertEqual(t, h.count, uint64(2), "Observation count")
+}
+
+func TestEscapeLabelValue(t *testing.T) {
+	assertEqual(t, escapeLabelValue(`say "hi"\n`+"\n"), `say \"hi\"\\n\n`, "Escaped label value")
+}
+
+func TestBot_Send_RecordsMetrics(t *testing.T) {
+	// Arrange
+	bot := &Bot{BotType: BotType(999)}
+
+	// Act
+	bot.SendMessage("C123", "hello")
+
+	// Assert
+	assertEqual(t, bot.metrics.sendFailures["unknown"], uint64(1), "Failed send is counted")
+}
@@ -0,0 +1,300 @@
+package botbooter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+)
+
+type botConfig struct {
+	slackAppToken string
+	slackBotToken string
+	discordToken  string
+	httpClient    *http.Client
+	apiURL        string
+	gatewayURL    string
+	intents       *discordgo.Intent
+	logger        Logger
+	dispatcher    *DispatcherOptions
+}
+
+type Option func(cfg *botConfig) error
+
+func WithSlackTokens(appToken, botToken string) Option {
+	return func(cfg *botConfig) error {
+		cfg.slackAppToken = appToken
+		cfg.slackBotToken = botToken
+		return nil
+	}
+}
+
+func WithDiscordToken(token string) Option {
+	return func(cfg *botConfig) error {
+		cfg.discordToken = strings.TrimPrefix(token, "Bot ")
+		return nil
+	}
+}
+
+func WithHTTPClient(client *http.Client) Option {
+	return func(cfg *botConfig) error {
+		if client == nil {
+			return errors.New("HTTP client is nil")
+		}
+		cfg.httpClient = client
+		return nil
+	}
+}
+
+// WithAPIURL replaces https://slack.com/api/ for Slack bots and https://discord.com/
+// for Discord bots.
+func WithAPIURL(apiURL string) Option {
+	return func(cfg *botConfig) error {
+		parsed, err := url.Parse(apiURL)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return fmt.Errorf("invalid API URL %q", apiURL)
+		}
+		cfg.apiURL = strings.TrimSuffix(apiURL, "/") + "/"
+		return nil
+	}
+}
+
+// WithGatewayURL sets the Discord gateway websocket URL instead of asking the API for it.
+func WithGatewayURL(gatewayURL string) Option {
+	return func(cfg *botConfig) error {
+		parsed, err := url.Parse(gatewayURL)
+		if err != nil || (parsed.Scheme != "ws" && parsed.Scheme != "wss") || parsed.Host == "" {
+			return fmt.Errorf("invalid gateway URL %q", gatewayURL)
+		}
+		cfg.gatewayURL = gatewayURL
+		return nil
+	}
+}
+
+func WithIntents(intents discordgo.Intent) Option {
+	return func(cfg *botConfig) error {
+		cfg.intents = &intents
+		return nil
+	}
+}
+
+func WithLogger(logger Logger) Option {
+	return func(cfg *botConfig) error {
+		cfg.logger = logger
+		return nil
+	}
+}
+
+func WithDispatcher(opts DispatcherOptions) Option {
+	return func(cfg *botConfig) error {
+		cfg.dispatcher = &opts
+		return nil
+	}
+}
+
+func applyOptions(opts []Option) (*botConfig, error) {
+	cfg := &botConfig{}
+	for _, opt := range opts {
+		if err := opt(cfg); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+func NewSlackBot(opts ...Option) (*Bot, error) {
+	cfg, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSlackConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	b := newSlackBot(cfg)
+	b.configure(cfg)
+	return b, nil
+}
+
+func NewDiscordBot(opts ...Option) (*Bot, error) {
+	cfg, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateDiscordConfig(cfg, true); err != nil {
+		return nil, err
+	}
+
+	b, err := newDiscordBot(cfg)
+	if err != nil {
+		return nil, err
+	}
+	b.configure(cfg)
+	return b, nil
+}
+
+// Reconfigure rebuilds the platform clients of an existing bot from opts while keeping
+// its handlers and settings, e.g. to point a bot from InitAsSlackBot at a local server.
+// Slack bots need WithSlackTokens because their clients are replaced; Discord sessions
+// are updated in place and keep their HTTP client unless WithHTTPClient is given. It
+// must be called before Connect.
+func (b *Bot) Reconfigure(opts ...Option) error {
+	cfg, err := applyOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	switch b.BotType {
+	case SlackBotType:
+		if err := validateSlackConfig(cfg); err != nil {
+			return err
+		}
+		rebuilt := newSlackBot(cfg)
+		b.SlackClient = rebuilt.SlackClient
+		b.SlackSocketClient = rebuilt.SlackSocketClient
+		b.slackBotToken = rebuilt.slackBotToken
+		b.apiURL = rebuilt.apiURL
+		b.httpClient = rebuilt.httpClient
+	case DiscordBotType:
+		if err := validateDiscordConfig(cfg, cfg.discordToken != ""); err != nil {
+			return err
+		}
+		if err := b.reconfigureDiscord(cfg); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown bot type")
+	}
+
+	b.configure(cfg)
+	return nil
+}
+
+func (b *Bot) reconfigureDiscord(cfg *botConfig) error {
+	base := cfg.httpClient
+	if base == nil {
+		base = b.DiscordSession.Client
+	}
+	client, err := discordHTTPClient(base, cfg.apiURL, cfg.gatewayURL)
+	if err != nil {
+		return err
+	}
+
+	if client != nil {
+		b.DiscordSession.Client = client
+	}
+	if cfg.httpClient != nil {
+		b.httpClient = cfg.httpClient
+	}
+	if cfg.discordToken != "" {
+		b.DiscordSession.Token = "Bot " + cfg.discordToken
+		b.DiscordSession.Identify.Token = b.DiscordSession.Token
+	}
+	if cfg.intents != nil {
+		b.DiscordSession.Identify.Intents = *cfg.intents
+	}
+	return nil
+}
+
+func validateDiscordConfig(cfg *botConfig, requireToken bool) error {
+	if requireToken {
+		if err := validateToken("Discord token", cfg.discordToken, ""); err != nil {
+			return err
+		}
+	}
+	if cfg.slackAppToken != "" || cfg.slackBotToken != "" {
+		return errors.New("Slack options cannot be used with a Discord bot")
+	}
+	return nil
+}
+
+func validateSlackConfig(cfg *botConfig) error {
+	if err := validateToken("Slack app token", cfg.slackAppToken, "xapp-"); err != nil {
+		return err
+	}
+	if err := validateToken("Slack bot token", cfg.slackBotToken, "xoxb-"); err != nil {
+		return err
+	}
+	if cfg.discordToken != "" || cfg.intents != nil || cfg.gatewayURL != "" {
+		return errors.New("Discord options cannot be used with a Slack bot")
+	}
+	return nil
+}
+
+func newSlackBot(cfg *botConfig) *Bot {
+	options := []slack.Option{slack.OptionAppLevelToken(cfg.slackAppToken)}
+	if cfg.httpClient != nil {
+		options = append(options, slack.OptionHTTPClient(cfg.httpClient))
+	}
+	if cfg.apiURL != "" {
+		options = append(options, slack.OptionAPIURL(cfg.apiURL))
+	}
+	client := slack.New(cfg.slackBotToken, options...)
+
+	return &Bot{
+		BotType:           SlackBotType,
+		SlackClient:       client,
+		SlackSocketClient: socketmode.New(client),
+		Commands:          []Command{},
+		slackBotToken:     cfg.slackBotToken,
+		apiURL:            cfg.apiURL,
+		httpClient:        cfg.httpClient,
+	}
+}
+
+func newDiscordBot(cfg *botConfig) (*Bot, error) {
+	dg, err := discordgo.New("Bot " + cfg.discordToken)
+	if err != nil {
+		return nil, err
+	}
+	client, err := discordHTTPClient(cfg.httpClient, cfg.apiURL, cfg.gatewayURL)
+	if err != nil {
+		return nil, err
+	}
+	if client != nil {
+		dg.Client = client
+	}
+	if cfg.intents != nil {
+		dg.Identify.Intents = *cfg.intents
+	}
+
+	return &Bot{
+		BotType:        DiscordBotType,
+		DiscordSession: dg,
+		Commands:       []Command{},
+		httpClient:     cfg.httpClient,
+	}, nil
+}
+
+func (b *Bot) configure(cfg *botConfig) {
+	if cfg.logger != nil {
+		b.Logger = cfg.logger
+	}
+	if cfg.dispatcher != nil {
+		b.EnableDispatcher(*cfg.dispatcher)
+	}
+}
+
+func validateToken(name, token, prefix string) error {
+	switch {
+	case token == "":
+		return fmt.Errorf("%s is required", name)
+	case strings.ContainsAny(token, " \t\r\n"):
+		return fmt.Errorf("%s must not contain whitespace", name)
+	case prefix != "" && !strings.HasPrefix(token, prefix):
+		return fmt.Errorf("%s must start with %q", name, prefix)
+	}
+	return nil
+}
+
+func (b *Bot) client() *http.Client {
+	if b.httpClient != nil {
+		return b.httpClient
+	}
+	return http.DefaultClient
+}
@@ -0,0 +1,164 @@
+package botbooter
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestNewSlackBot(t *testing.T) {
+	// Arrange
+	client := &http.Client{}
+	logger := NewNopLogger()
+
+	// Act
+	bot, err := NewSlackBot(
+		WithSlackTokens("xapp-test", "xoxb-test"),
+		WithHTTPClient(client),
+		WithAPIURL("http://127.0.0.1:8080/api"),
+		WithLogger(logger),
+		WithDispatcher(DispatcherOptions{Workers: 2}),
+	)
+
+	// Assert
+	assertNoError(t, err, "NewSlackBot should not fail")
+	assertEqual(t, bot.BotType, SlackBotType, "Bot type")
+	assertNotNil(t, bot.SlackClient, "Slack client")
+	assertNotNil(t, bot.SlackSocketClient, "Socket mode client")
+	assertTrue(t, bot.client() == client, "HTTP client")
+	assertTrue(t, bot.Logger == logger, "Logger")
+	assertEqual(t, cap(bot.dispatcher.workers), 2, "Dispatcher workers")
+}
+
+func TestNewSlackBot_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"missing tokens", nil},
+		{"app token prefix", []Option{WithSlackTokens("xoxb-test", "xoxb-test")}},
+		{"bot token prefix", []Option{WithSlackTokens("xapp-test", "xoxp-test")}},
+		{"whitespace", []Option{WithSlackTokens("xapp-test", "xoxb-test\n")}},
+		{"invalid API URL", []Option{WithSlackTokens("xapp-test", "xoxb-test"), WithAPIURL("localhost")}},
+		{"nil HTTP client", []Option{WithSlackTokens("xapp-test", "xoxb-test"), WithHTTPClient(nil)}},
+		{"Discord option", []Option{WithSlackTokens("xapp-test", "xoxb-test"), WithIntents(discordgo.IntentsGuildMessages)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot, err := NewSlackBot(tt.opts...)
+			assertError(t, err, "NewSlackBot should fail")
+			assertTrue(t, bot == nil, "No bot on error")
+		})
+	}
+}
+
+func TestNewDiscordBot(t *testing.T) {
+	// Arrange
+	client := &http.Client{}
+	intents := discordgo.IntentsGuildMessages | discordgo.IntentsMessageContent
+
+	// Act
+	bot, err := NewDiscordBot(
+		WithDiscordToken("Bot test_token"),
+		WithHTTPClient(client),
+		WithIntents(intents),
+	)
+
+	// Assert
+	assertNoError(t, err, "NewDiscordBot should not fail")
+	assertEqual(t, bot.BotType, DiscordBotType, "Bot type")
+	assertEqual(t, bot.DiscordSession.Token, "Bot test_token", "Token is not prefixed twice")
+	assertTrue(t, bot.DiscordSession.Client == client, "HTTP client")
+	assertEqual(t, bot.DiscordSession.Identify.Intents, intents, "Gateway intents")
+}
+
+func TestNewDiscordBot_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"missing token", nil},
+		{"whitespace", []Option{WithDiscordToken("test token")}},
+		{"Slack option", []Option{WithDiscordToken("test_token"), WithSlackTokens("xapp-test", "xoxb-test")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot, err := NewDiscordBot(tt.opts...)
+			assertError(t, err, "NewDiscordBot should fail")
+			assertTrue(t, bot == nil, "No bot on error")
+		})
+	}
+}
+
+func TestInitAsSlackBot_SetsBotType(t *testing.T) {
+	// Act
+	bot := InitAsSlackBot("xapp-test", "xoxb-test")
+
+	// Assert
+	assertEqual(t, bot.BotType, SlackBotType, "Bot type")
+	assertTrue(t, bot.client() == http.DefaultClient, "Default HTTP client")
+}
+
+func TestBot_Reconfigure_Slack(t *testing.T) {
+	// Arrange
+	bot := InitAsSlackBot("xapp-test", "xoxb-test")
+	bot.AddHandler(Command{Pattern: "^ping$", Handler: func(bot *Bot, message *Message) {}})
+	previous := bot.SlackClient
+
+	// Act
+	err := bot.Reconfigure(WithSlackTokens("xapp-other", "xoxb-other"), WithAPIURL("http://127.0.0.1:8080/api"))
+	invalidErr := bot.Reconfigure(WithAPIURL("http://127.0.0.1:8080/api"))
+
+	// Assert
+	assertNoError(t, err, "Reconfigure should not fail")
+	assertTrue(t, bot.SlackClient != previous, "Slack client should be rebuilt")
+	assertEqual(t, bot.slackBotToken, "xoxb-other", "Bot token")
+	assertEqual(t, bot.apiURL, "http://127.0.0.1:8080/api/", "API URL")
+	assertEqual(t, len(bot.Commands), 1, "Handlers should be kept")
+	assertError(t, invalidErr, "Slack bots need tokens to be reconfigured")
+}
+
+func TestBot_Reconfigure_UnknownBotType(t *testing.T) {
+	// Arrange
+	bot := &Bot{BotType: BotType(999)}
+
+	// Act
+	err := bot.Reconfigure()
+
+	// Assert
+	assertError(t, err, "Unknown bot type")
+}
+
+func TestBot_Reconfigure_Discord(t *testing.T) {
+	// Arrange
+	var host, authorization string
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		host = r.URL.Host
+		authorization = r.Header.Get("Authorization")
+		return nil, errors.New("offline")
+	})
+	bot := InitAsDiscordBot("test_token")
+	bot.DiscordSession.Client = &http.Client{Transport: transport}
+
+	// Act
+	err := bot.Reconfigure(WithDiscordToken("other_token"), WithAPIURL("http://127.0.0.1:8080"), WithIntents(discordgo.IntentsGuildMessages))
+	bot.DiscordSession.Channel("channel123")
+	slackErr := bot.Reconfigure(WithSlackTokens("xapp-test", "xoxb-test"))
+
+	// Assert
+	assertNoError(t, err, "Reconfigure should not fail")
+	assertEqual(t, host, "127.0.0.1:8080", "Requests should be redirected to the API URL")
+	assertEqual(t, authorization, "Bot other_token", "Requests should use the bot's transport and new token")
+	assertEqual(t, bot.DiscordSession.Identify.Intents, discordgo.IntentsGuildMessages, "Intents")
+	assertError(t, slackErr, "Slack options cannot be used with a Discord bot")
+}
+
+type roundTripperFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,111 @@
+package botbooter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+var errMissingMessageID = errors.New("message has no message ID")
+
+type Reaction struct {
+	Emoji       string
+	UserID      string
+	ChannelID   string
+	MessageID   string
+	SlackData   *slackevents.ReactionAddedEvent
+	DiscordData *discordgo.MessageReactionAdd
+}
+
+type ReactionHandler func(bot *Bot, reaction *Reaction)
+
+func (b *Bot) OnReaction(emoji string, handler ReactionHandler) {
+	if b.ReactionHandlers == nil {
+		b.ReactionHandlers = map[string]ReactionHandler{}
+	}
+	b.ReactionHandlers[NormalizeEmoji(emoji)] = handler
+}
+
+func (b *Bot) React(message *Message, emoji string) error {
+	switch b.BotType {
+	case SlackBotType:
+		if message.MessageID == "" {
+			return errMissingMessageID
+		}
+		return b.SlackClient.AddReaction(slackEmoji(emoji), slack.NewRefToMessage(message.ChannelID, message.MessageID))
+	case DiscordBotType:
+		if message.MessageID == "" {
+			return errMissingMessageID
+		}
+		return b.DiscordSession.MessageReactionAdd(message.ChannelID, message.MessageID, discordEmoji(emoji))
+	default:
+		return fmt.Errorf("unknown bot type")
+	}
+}
+
+func (b *Bot) Unreact(message *Message, emoji string) error {
+	switch b.BotType {
+	case SlackBotType:
+		if message.MessageID == "" {
+			return errMissingMessageID
+		}
+		return b.SlackClient.RemoveReaction(slackEmoji(emoji), slack.NewRefToMessage(message.ChannelID, message.MessageID))
+	case DiscordBotType:
+		if message.MessageID == "" {
+			return errMissingMessageID
+		}
+		return b.DiscordSession.MessageReactionRemove(message.ChannelID, message.MessageID, discordEmoji(emoji), "@me")
+	default:
+		return fmt.Errorf("unknown bot type")
+	}
+}
+
+func (b *Bot) handleReaction(reaction *Reaction) {
+	handler, ok := b.ReactionHandlers[reaction.Emoji]
+	if !ok {
+		b.logger().Debug("No handler for reaction", "emoji", reaction.Emoji)
+		return
+	}
+	if !b.handlers.begin() {
+		return
+	}
+	defer b.handlers.end()
+
+	handler(b, reaction)
+}
+
+func (b *Bot) handleSlackReactionAdded(ev *slackevents.ReactionAddedEvent) {
+	if b.slackBotUserID != "" && ev.User == b.slackBotUserID {
+		return
+	}
+
+	b.handleReaction(&Reaction{
+		Emoji:     NormalizeEmoji(ev.Reaction),
+		UserID:    ev.User,
+		ChannelID: ev.Item.Channel,
+		MessageID: ev.Item.Timestamp,
+		SlackData: ev,
+	})
+}
+
+func (b *Bot) handleDiscordReactionAdd(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+	if s.State != nil && s.State.User != nil && r.UserID == s.State.User.ID {
+		return
+	}
+
+	emoji := r.Emoji.Name
+	if r.Emoji.ID != "" {
+		emoji = r.Emoji.Name + ":" + r.Emoji.ID
+	}
+
+	b.handleReaction(&Reaction{
+		Emoji:       NormalizeEmoji(emoji),
+		UserID:      r.UserID,
+		ChannelID:   r.ChannelID,
+		MessageID:   r.MessageID,
+		DiscordData: r,
+	})
+}
@@ -0,0 +1,121 @@
+package botbooter
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/slack-go/slack/slackevents"
+)
+
+func TestBot_OnReaction(t *testing.T) {
+	// Arrange
+	bot := &Bot{}
+
+	// Act
+	bot.OnReaction("✅", func(bot *Bot, reaction *Reaction) {})
+
+	// Assert
+	_, ok := bot.ReactionHandlers[":white_check_mark:"]
+	assertTrue(t, ok, "Handler should be registered under the normalized name")
+}
+
+func TestBot_React_MissingMessageID(t *testing.T) {
+	t.Run("SlackBot", func(t *testing.T) {
+		// Arrange
+		bot := InitAsSlackBot("xapp-test", "xoxb-test")
+		message := &Message{ChannelID: "C456", Content: "hello"}
+
+		// Act
+		err := bot.React(message, "eyes")
+
+		// Assert
+		assertEqual(t, err, errMissingMessageID, "React without message ID should fail")
+	})
+
+	t.Run("DiscordBot", func(t *testing.T) {
+		// Arrange
+		bot := InitAsDiscordBot("test_token")
+		message := &Message{ChannelID: "channel123", Content: "hello"}
+
+		// Act
+		err := bot.Unreact(message, "eyes")
+
+		// Assert
+		assertEqual(t, err, errMissingMessageID, "Unreact without message ID should fail")
+	})
+
+	t.Run("UnknownBotType", func(t *testing.T) {
+		// Arrange
+		bot := &Bot{BotType: BotType(999)}
+
+		// Act
+		err := bot.React(&Message{}, "eyes")
+
+		// Assert
+		assertError(t, err, "React with unknown bot type should fail")
+		assertEqual(t, err.Error(), "unknown bot type", "Error message for unknown bot type")
+	})
+}
+
+func TestHandleSlackEventsApi_ReactionAdded(t *testing.T) {
+	// Arrange
+	bot := InitAsSlackBot("xapp-test", "xoxb-test")
+	bot.slackBotUserID = "UBOT"
+	var received []*Reaction
+	bot.OnReaction(":white_check_mark:", func(bot *Bot, reaction *Reaction) {
+		received = append(received, reaction)
+	})
+
+	reactionEvent := func(userID string) slackevents.EventsAPIEvent {
+		return slackevents.EventsAPIEvent{
+			InnerEvent: slackevents.EventsAPIInnerEvent{
+				Data: &slackevents.ReactionAddedEvent{
+					User:     userID,
+					Reaction: "white_check_mark",
+					Item: slackevents.Item{
+						Channel:   "C456",
+						Timestamp: "1700000000.000100",
+					},
+				},
+			},
+		}
+	}
+
+	// Act
+	bot.handleSlackEventsApi(reactionEvent("U123"))
+	bot.handleSlackEventsApi(reactionEvent("UBOT"))
+
+	// Assert
+	assertEqual(t, len(received), 1, "Only reactions from other users should be routed")
+	assertEqual(t, received[0].UserID, "U123", "User ID")
+	assertEqual(t, received[0].ChannelID, "C456", "Channel ID")
+	assertEqual(t, received[0].MessageID, "1700000000.000100", "Message ID")
+}
+
+func TestHandleDiscordReactionAdd(t *testing.T) {
+	// Arrange
+	bot := InitAsDiscordBot("test_token")
+	session := &discordgo.Session{State: discordgo.NewState()}
+	session.State.User = &discordgo.User{ID: "bot123"}
+	var received []*Reaction
+	bot.OnReaction(":white_check_mark:", func(bot *Bot, reaction *Reaction) {
+		received = append(received, reaction)
+	})
+
+	// Act
+	bot.handleDiscordReactionAdd(session, &discordgo.MessageReactionAdd{MessageReaction: &discordgo.MessageReaction{
+		UserID:    "user123",
+		MessageID: "message123",
+		ChannelID: "channel123",
+		Emoji:     discordgo.Emoji{Name: "✅"},
+	}})
+	bot.handleDiscordReactionAdd(session, &discordgo.MessageReactionAdd{MessageReaction: &discordgo.MessageReaction{
+		UserID: "bot123",
+		Emoji:  discordgo.Emoji{Name: "✅"},
+	}})
+
+	// Assert
+	assertEqual(t, len(received), 1, "Only reactions from other users should be routed")
+	assertEqual(t, received[0].Emoji, ":white_check_mark:", "Normalized emoji")
+	assertEqual(t, received[0].MessageID, "message123", "Message ID")
+}
@@ -0,0 +1,316 @@
+package botbooter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/slack-go/slack"
+)
+
+const (
+	discordEmbedFields   = 25
+	discordMessageEmbeds = 10
+)
+
+type RichMessage struct {
+	Title       string
+	Text        string
+	Sections    []RichSection
+	Fields      []RichField
+	Images      []RichImage
+	CodeBlocks  []CodeBlock
+	Buttons     []Button
+	SelectMenus []SelectMenu
+	Color       string
+	Footer      string
+}
+
+type RichSection struct {
+	Title string
+	Text  string
+}
+
+type RichField struct {
+	Name   string
+	Value  string
+	Inline bool
+}
+
+type RichImage struct {
+	URL     string
+	AltText string
+}
+
+type CodeBlock struct {
+	Language string
+	Code     string
+}
+
+func (m *RichMessage) PlainText() string {
+	var parts []string
+
+	if m.Title != "" {
+		parts = append(parts, m.Title)
+	}
+	if m.Text != "" {
+		parts = append(parts, m.Text)
+	}
+	for _, section := range m.Sections {
+		parts = append(parts, joinNonEmpty("\n", section.Title, section.Text))
+	}
+	if len(m.Fields) > 0 {
+		var fields []string
+		for _, field := range m.Fields {
+			fields = append(fields, field.Name+": "+field.Value)
+		}
+		parts = append(parts, strings.Join(fields, "\n"))
+	}
+	for _, block := range m.CodeBlocks {
+		parts = append(parts, block.markdown())
+	}
+	for _, image := range m.Images {
+		parts = append(parts, image.URL)
+	}
+	if m.Footer != "" {
+		parts = append(parts, m.Footer)
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+func (c CodeBlock) markdown() string {
+	return "```" + c.Language + "\n" + strings.TrimRight(c.Code, "\n") + "\n```"
+}
+
+func joinNonEmpty(sep string, values ...string) string {
+	var parts []string
+	for _, value := range values {
+		if value != "" {
+			parts = append(parts, value)
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+func parseHexColor(color string) (int, error) {
+	value, err := strconv.ParseUint(strings.TrimPrefix(color, "#"), 16, 32)
+	if err != nil || value > 0xFFFFFF {
+		return 0, fmt.Errorf("invalid color %q", color)
+	}
+	return int(value), nil
+}
+
+func (m *RichMessage) slackBlocks() []slack.Block {
+	var blocks []slack.Block
+
+	if m.Title != "" {
+		blocks = append(blocks, slack.NewHeaderBlock(
+			slack.NewTextBlockObject(slack.PlainTextType, m.Title, true, false),
+		))
+	}
+	if m.Text != "" {
+		blocks = append(blocks, slackMarkdownSection(m.Text))
+	}
+	for _, section := range m.Sections {
+		text := section.Text
+		if section.Title != "" {
+			text = joinNonEmpty("\n", "*"+section.Title+"*", section.Text)
+		}
+		blocks = append(blocks, slackMarkdownSection(text))
+	}
+	// Slack allows at most 10 fields per section block.
+	for start := 0; start < len(m.Fields); start += 10 {
+		end := start + 10
+		if end > len(m.Fields) {
+			end = len(m.Fields)
+		}
+		var fields []*slack.TextBlockObject
+		for _, field := range m.Fields[start:end] {
+			fields = append(fields, slack.NewTextBlockObject(slack.MarkdownType, "*"+field.Name+"*\n"+field.Value, false, false))
+		}
+		blocks = append(blocks, slack.NewSectionBlock(nil, fields, nil))
+	}
+	for _, block := range m.CodeBlocks {
+		// Slack does not highlight code, so the language hint is dropped.
+		blocks = append(blocks, slackMarkdownSection(CodeBlock{Code: block.Code}.markdown()))
+	}
+	for _, image := range m.Images {
+		altText := image.AltText
+		if altText == "" {
+			altText = image.URL
+		}
+		blocks = append(blocks, slack.NewImageBlock(image.URL, altText, "", nil))
+	}
+	if actions := m.slackActionBlock(); actions != nil {
+		blocks = append(blocks, actions)
+	}
+	if m.Footer != "" {
+		blocks = append(blocks, slack.NewContextBlock("",
+			slack.NewTextBlockObject(slack.MarkdownType, m.Footer, false, false),
+		))
+	}
+
+	return blocks
+}
+
+func slackMarkdownSection(text string) *slack.SectionBlock {
+	return slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil)
+}
+
+func (m *RichMessage) slackMessageOptions() ([]slack.MsgOption, error) {
+	if m.Color == "" {
+		return []slack.MsgOption{
+			slack.MsgOptionText(m.PlainText(), false),
+			slack.MsgOptionBlocks(m.slackBlocks()...),
+		}, nil
+	}
+
+	if _, err := parseHexColor(m.Color); err != nil {
+		return nil, err
+	}
+
+	// Block Kit has no color, so colored messages are wrapped in a legacy attachment. Slack
+	// shows the top-level text above attachments, so it only carries the title.
+	return []slack.MsgOption{
+		slack.MsgOptionText(m.Title, false),
+		slack.MsgOptionAttachments(slack.Attachment{
+			Color:    "#" + strings.TrimPrefix(m.Color, "#"),
+			Fallback: m.PlainText(),
+			Blocks:   slack.Blocks{BlockSet: m.slackBlocks()},
+		}),
+	}, nil
+}
+
+func (m *RichMessage) discordEmbeds() ([]*discordgo.MessageEmbed, error) {
+	embed := &discordgo.MessageEmbed{
+		Title: m.Title,
+	}
+
+	if m.Color != "" {
+		color, err := parseHexColor(m.Color)
+		if err != nil {
+			return nil, err
+		}
+		embed.Color = color
+	}
+
+	var description []string
+	if m.Text != "" {
+		description = append(description, m.Text)
+	}
+	for _, section := range m.Sections {
+		title := section.Title
+		if title != "" {
+			title = "**" + title + "**"
+		}
+		description = append(description, joinNonEmpty("\n", title, section.Text))
+	}
+	for _, block := range m.CodeBlocks {
+		description = append(description, block.markdown())
+	}
+	embed.Description = strings.Join(description, "\n\n")
+
+	var fields []*discordgo.MessageEmbedField
+	for _, field := range m.Fields {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   field.Name,
+			Value:  field.Value,
+			Inline: field.Inline,
+		})
+	}
+	// Discord allows at most 25 fields per embed, so the rest continue in further embeds.
+	var continued []*discordgo.MessageEmbed
+	for start := 0; start < len(fields); start += discordEmbedFields {
+		end := start + discordEmbedFields
+		if end > len(fields) {
+			end = len(fields)
+		}
+		if start == 0 {
+			embed.Fields = fields[start:end]
+			continue
+		}
+		continued = append(continued, &discordgo.MessageEmbed{
+			Color:  embed.Color,
+			Fields: fields[start:end],
+		})
+	}
+
+	if m.Footer != "" {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: m.Footer}
+	}
+
+	var images []*discordgo.MessageEmbed
+	for i, image := range m.Images {
+		if i == 0 {
+			embed.Image = &discordgo.MessageEmbedImage{URL: image.URL}
+			continue
+		}
+		// Additional images become image-only embeds, which Discord renders as a gallery.
+		images = append(images, &discordgo.MessageEmbed{
+			Color: embed.Color,
+			Image: &discordgo.MessageEmbedImage{URL: image.URL},
+		})
+	}
+
+	// Discord rejects empty embeds, e.g. for a message that only has buttons.
+	var embeds []*discordgo.MessageEmbed
+	if embed.Title != "" || embed.Description != "" || len(embed.Fields) > 0 || embed.Footer != nil || embed.Image != nil {
+		embeds = append(embeds, embed)
+	}
+	embeds = append(embeds, continued...)
+	embeds = append(embeds, images...)
+	if len(embeds) > discordMessageEmbeds {
+		return nil, fmt.Errorf("rich message needs %d embeds, Discord allows %d", len(embeds), discordMessageEmbeds)
+	}
+
+	return embeds, nil
+}
+
+func (m *RichMessage) discordMessageSend() (*discordgo.MessageSend, error) {
+	embeds, err := m.discordEmbeds()
+	if err != nil {
+		return nil, err
+	}
+	components, err := m.discordComponents()
+	if err != nil {
+		return nil, err
+	}
+
+	return &discordgo.MessageSend{
+		Embeds:     embeds,
+		Components: components,
+	}, nil
+}
+
+func (b *Bot) SendRichMessage(channelID string, message *RichMessage) error {
+	return b.QueueRichMessage(channelID, message).Wait()
+}
+
+func (b *Bot) QueueRichMessage(channelID string, message *RichMessage) *Delivery {
+	return b.send(channelID, func() error {
+		return b.postRichMessage(channelID, message)
+	})
+}
+
+func (b *Bot) postRichMessage(channelID string, message *RichMessage) error {
+	switch b.BotType {
+	case SlackBotType:
+		options, err := message.slackMessageOptions()
+		if err != nil {
+			return err
+		}
+		_, _, err = b.SlackClient.PostMessage(channelID, options...)
+		return err
+	case DiscordBotType:
+		send, err := message.discordMessageSend()
+		if err != nil {
+			return err
+		}
+		_, err = b.DiscordSession.ChannelMessageSendComplex(channelID, send)
+		return err
+	default:
+		return fmt.Errorf("unknown bot type")
+	}
+}
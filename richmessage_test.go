@@ -0,0 +1,215 @@
+package botbooter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func newTestRichMessage() *RichMessage {
+	return &RichMessage{
+		Title: "Deploy finished",
+		Text:  "Version 1.2.3 is live",
+		Sections: []RichSection{
+			{Title: "Changes", Text: "Fixed login"},
+		},
+		Fields: []RichField{
+			{Name: "Environment", Value: "production", Inline: true},
+			{Name: "Duration", Value: "42s", Inline: true},
+		},
+		Images: []RichImage{
+			{URL: "https://example.com/chart1.png", AltText: "chart"},
+			{URL: "https://example.com/chart2.png"},
+		},
+		CodeBlocks: []CodeBlock{
+			{Language: "bash", Code: "make deploy\n"},
+		},
+		Color:  "#36a64f",
+		Footer: "deploy-bot",
+	}
+}
+
+func TestRichMessage_PlainText(t *testing.T) {
+	// Arrange
+	message := newTestRichMessage()
+	expected := "Deploy finished\n\n" +
+		"Version 1.2.3 is live\n\n" +
+		"Changes\nFixed login\n\n" +
+		"Environment: production\nDuration: 42s\n\n" +
+		"```bash\nmake deploy\n```\n\n" +
+		"https://example.com/chart1.png\n\n" +
+		"https://example.com/chart2.png\n\n" +
+		"deploy-bot"
+
+	// Act
+	text := message.PlainText()
+
+	// Assert
+	assertEqual(t, text, expected, "Plain text rendering")
+}
+
+func TestRichMessage_SlackBlocks(t *testing.T) {
+	// Arrange
+	message := newTestRichMessage()
+	expectedTypes := []slack.MessageBlockType{
+		slack.MBTHeader,
+		slack.MBTSection,
+		slack.MBTSection,
+		slack.MBTSection,
+		slack.MBTSection,
+		slack.MBTImage,
+		slack.MBTImage,
+		slack.MBTContext,
+	}
+
+	// Act
+	blocks := message.slackBlocks()
+
+	// Assert
+	assertEqual(t, len(blocks), len(expectedTypes), "Number of blocks")
+	for i := 0; i < len(blocks) && i < len(expectedTypes); i++ {
+		assertEqual(t, blocks[i].BlockType(), expectedTypes[i], "Block type")
+	}
+	fields := blocks[3].(*slack.SectionBlock).Fields
+	assertEqual(t, len(fields), 2, "Number of section fields")
+	assertEqual(t, fields[0].Text, "*Environment*\nproduction", "Field text")
+}
+
+func TestRichMessage_SlackBlocksSplitFields(t *testing.T) {
+	// Arrange
+	message := &RichMessage{}
+	for i := 0; i < 12; i++ {
+		message.Fields = append(message.Fields, RichField{Name: "name", Value: "value"})
+	}
+
+	// Act
+	blocks := message.slackBlocks()
+
+	// Assert
+	assertEqual(t, len(blocks), 2, "Fields should be split into sections of ten")
+	assertEqual(t, len(blocks[1].(*slack.SectionBlock).Fields), 2, "Fields in second section")
+}
+
+func TestRichMessage_DiscordEmbeds(t *testing.T) {
+	// Arrange
+	message := newTestRichMessage()
+
+	// Act
+	embeds, err := message.discordEmbeds()
+
+	// Assert
+	assertNoError(t, err, "Rendering embeds should not fail")
+	assertEqual(t, len(embeds), 2, "Number of embeds")
+	assertEqual(t, embeds[0].Title, "Deploy finished", "Embed title")
+	assertEqual(t, embeds[0].Color, 0x36a64f, "Embed color")
+	assertEqual(t, len(embeds[0].Fields), 2, "Number of embed fields")
+	assertTrue(t, embeds[0].Fields[0].Inline, "Field should be inline")
+	assertEqual(t, embeds[0].Footer.Text, "deploy-bot", "Embed footer")
+	assertEqual(t, embeds[0].Image.URL, "https://example.com/chart1.png", "First image")
+	assertEqual(t, embeds[1].Image.URL, "https://example.com/chart2.png", "Second image")
+	assertTrue(t, strings.Contains(embeds[0].Description, "```bash\nmake deploy\n```"), "Description should contain code block")
+	assertTrue(t, strings.Contains(embeds[0].Description, "**Changes**\nFixed login"), "Description should contain section")
+}
+
+func TestRichMessage_DiscordEmbedsButtonsOnly(t *testing.T) {
+	// Arrange
+	message := &RichMessage{Buttons: []Button{{ActionID: "approve", Label: "Approve"}}}
+
+	// Act
+	send, err := message.discordMessageSend()
+
+	// Assert
+	assertNoError(t, err, "Rendering should not fail")
+	assertEqual(t, len(send.Embeds), 0, "Empty embeds should be omitted")
+	assertEqual(t, len(send.Components), 1, "Buttons should still be sent")
+}
+
+func TestRichMessage_DiscordEmbedsSplitFields(t *testing.T) {
+	// Arrange
+	message := &RichMessage{Title: "Inventory"}
+	for i := 0; i < 30; i++ {
+		message.Fields = append(message.Fields, RichField{Name: "name", Value: "value"})
+	}
+
+	// Act
+	embeds, err := message.discordEmbeds()
+
+	// Assert
+	assertNoError(t, err, "Rendering should not fail")
+	assertEqual(t, len(embeds), 2, "Fields should be split across embeds")
+	assertEqual(t, len(embeds[0].Fields), 25, "Fields in first embed")
+	assertEqual(t, len(embeds[1].Fields), 5, "Fields in second embed")
+}
+
+func TestRichMessage_DiscordEmbedsTooMany(t *testing.T) {
+	// Arrange
+	message := &RichMessage{}
+	for i := 0; i < 11*25; i++ {
+		message.Fields = append(message.Fields, RichField{Name: "name", Value: "value"})
+	}
+
+	// Act
+	_, err := message.discordEmbeds()
+
+	// Assert
+	assertError(t, err, "More than ten embeds should be rejected")
+}
+
+func TestRichMessage_SlackMessageOptionsColor(t *testing.T) {
+	// Arrange
+	message := newTestRichMessage()
+
+	// Act
+	options, err := message.slackMessageOptions()
+	_, values, applyErr := slack.UnsafeApplyMsgOptions("xoxb-test", "channel123", "", options...)
+
+	// Assert
+	assertNoError(t, err, "Rendering should not fail")
+	assertNoError(t, applyErr, "Applying options should not fail")
+	assertEqual(t, values.Get("text"), "Deploy finished", "Top-level text should only carry the title")
+	assertEqual(t, values.Get("blocks"), "", "Blocks should only be sent in the attachment")
+	assertTrue(t, strings.Contains(values.Get("attachments"), `"color":"#36a64f"`), "Attachment should carry the color")
+	assertTrue(t, strings.Contains(values.Get("attachments"), "Version 1.2.3 is live"), "Attachment should carry the content")
+}
+
+func TestRichMessage_InvalidColor(t *testing.T) {
+	// Arrange
+	message := &RichMessage{Title: "title", Color: "green"}
+
+	// Act
+	_, discordErr := message.discordEmbeds()
+	_, slackErr := message.slackMessageOptions()
+
+	// Assert
+	assertError(t, discordErr, "Discord rendering should reject invalid color")
+	assertError(t, slackErr, "Slack rendering should reject invalid color")
+}
+
+func TestBot_SendRichMessage(t *testing.T) {
+	t.Run("UnknownBotType", func(t *testing.T) {
+		// Arrange
+		bot := &Bot{
+			BotType: BotType(999),
+		}
+
+		// Act
+		err := bot.SendRichMessage("channel123", &RichMessage{Title: "title"})
+
+		// Assert
+		assertError(t, err, "SendRichMessage with unknown bot type should fail")
+		assertEqual(t, err.Error(), "unknown bot type", "Error message for unknown bot type")
+	})
+
+	t.Run("DiscordBot", func(t *testing.T) {
+		// Arrange
+		bot := InitAsDiscordBot("test_token")
+
+		// Act
+		err := bot.SendRichMessage("channel123", newTestRichMessage())
+
+		// Assert
+		// We expect an error because we're not actually connected
+		assertError(t, err, "SendRichMessage without connection should fail")
+	})
+}
@@ -0,0 +1,174 @@
+package botbooter
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Job func(bot *Bot)
+
+type ScheduleOptions struct {
+	Location     *time.Location
+	Jitter       time.Duration
+	AllowOverlap bool
+}
+
+type ScheduledJob struct {
+	Spec string
+
+	schedule schedule
+	job      Job
+	options  ScheduleOptions
+	running  int32
+	stopOnce sync.Once
+	stopped  chan struct{}
+}
+
+func (j *ScheduledJob) Stop() {
+	j.stopOnce.Do(func() {
+		close(j.stopped)
+	})
+}
+
+type scheduler struct {
+	mu      sync.Mutex
+	jobs    []*ScheduledJob
+	stop    chan struct{}
+	loops   sync.WaitGroup
+	runs    sync.WaitGroup
+	now     func() time.Time
+	after   func(d time.Duration) <-chan time.Time
+	jitter  func(max time.Duration) time.Duration
+	started bool
+}
+
+func (b *Bot) Schedule(spec string, job Job) (*ScheduledJob, error) {
+	return b.ScheduleWithOptions(spec, job, ScheduleOptions{})
+}
+
+func (b *Bot) ScheduleWithOptions(spec string, job Job, opts ScheduleOptions) (*ScheduledJob, error) {
+	parsed, err := parseSchedule(spec, opts.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduled := &ScheduledJob{
+		Spec:     spec,
+		schedule: parsed,
+		job:      job,
+		options:  opts,
+		stopped:  make(chan struct{}),
+	}
+	b.scheduler.add(b, scheduled)
+	return scheduled, nil
+}
+
+func (s *scheduler) add(b *Bot, job *ScheduledJob) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs = append(s.jobs, job)
+	if s.started {
+		s.loops.Add(1)
+		go s.loop(b, job, s.stop)
+	}
+}
+
+func (s *scheduler) start(b *Bot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return
+	}
+	s.started = true
+	s.stop = make(chan struct{})
+	for _, job := range s.jobs {
+		s.loops.Add(1)
+		go s.loop(b, job, s.stop)
+	}
+}
+
+// shutdown stops all schedule loops and waits for jobs that are already running.
+func (s *scheduler) shutdown() {
+	s.mu.Lock()
+	if !s.started {
+		s.mu.Unlock()
+		return
+	}
+	s.started = false
+	close(s.stop)
+	s.mu.Unlock()
+
+	s.loops.Wait()
+	s.runs.Wait()
+}
+
+func (s *scheduler) loop(b *Bot, job *ScheduledJob, stop chan struct{}) {
+	defer s.loops.Done()
+
+	for {
+		now := s.clock()
+		next := job.schedule.next(now)
+		if next.IsZero() {
+			return
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-job.stopped:
+			return
+		case <-s.wait(next.Sub(now) + s.randomJitter(job.options.Jitter)):
+		}
+
+		s.run(b, job)
+	}
+}
+
+func (s *scheduler) run(b *Bot, job *ScheduledJob) {
+	if !job.options.AllowOverlap && !atomic.CompareAndSwapInt32(&job.running, 0, 1) {
+		b.logger().Warn("Skipping scheduled job, previous run still in progress", "spec", job.Spec)
+		return
+	}
+
+	s.runs.Add(1)
+	go func() {
+		defer s.runs.Done()
+		defer atomic.StoreInt32(&job.running, 0)
+		defer func() {
+			if r := recover(); r != nil {
+				b.logger().Error("Scheduled job panicked", "spec", job.Spec, "panic", r)
+			}
+		}()
+
+		b.logger().Debug("Running scheduled job", "spec", job.Spec)
+		job.job(b)
+	}()
+}
+
+func (s *scheduler) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+func (s *scheduler) wait(d time.Duration) <-chan time.Time {
+	if s.after != nil {
+		return s.after(d)
+	}
+	return time.After(d)
+}
+
+func (s *scheduler) randomJitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	if s.jitter != nil {
+		return s.jitter(max)
+	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
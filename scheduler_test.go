@@ -0,0 +1,100 @@
+package botbooter
+
+import (
+	"testing"
+	"time"
+)
+
+type fakeTimers struct {
+	waits chan time.Duration
+	fire  chan time.Time
+}
+
+func newSchedulerTestBot() (*Bot, *fakeTimers) {
+	timers := &fakeTimers{waits: make(chan time.Duration, 10), fire: make(chan time.Time)}
+	bot := &Bot{BotType: BotType(999)}
+	bot.scheduler.now = func() time.Time { return time.Date(2024, 1, 15, 8, 30, 0, 0, time.UTC) }
+	bot.scheduler.after = func(d time.Duration) <-chan time.Time {
+		timers.waits <- d
+		return timers.fire
+	}
+	bot.scheduler.jitter = func(max time.Duration) time.Duration { return max / 2 }
+	return bot, timers
+}
+
+func TestScheduler_RunsJobs(t *testing.T) {
+	// Arrange
+	bot, timers := newSchedulerTestBot()
+	runs := make(chan *Bot, 1)
+	_, err := bot.ScheduleWithOptions("0 9 * * *", func(bot *Bot) {
+		runs <- bot
+	}, ScheduleOptions{Location: time.UTC, Jitter: time.Minute})
+	assertNoError(t, err, "Schedule should not fail")
+
+	// Act
+	bot.scheduler.start(bot)
+	wait := <-timers.waits
+	timers.fire <- time.Time{}
+	ran := <-runs
+	<-timers.waits
+	bot.scheduler.shutdown()
+
+	// Assert
+	assertEqual(t, wait, 30*time.Minute+30*time.Second, "Delay until 9am plus jitter")
+	assertTrue(t, ran == bot, "Job receives the bot")
+}
+
+func TestScheduler_PreventsOverlap(t *testing.T) {
+	// Arrange
+	bot, timers := newSchedulerTestBot()
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	_, err := bot.Schedule("@every 1m", func(bot *Bot) {
+		started <- struct{}{}
+		<-release
+	})
+	assertNoError(t, err, "Schedule should not fail")
+	bot.scheduler.start(bot)
+
+	// Act
+	<-timers.waits
+	timers.fire <- time.Time{}
+	<-started
+	<-timers.waits
+	timers.fire <- time.Time{}
+	<-timers.waits
+
+	// Assert
+	assertEqual(t, len(started), 0, "Second run should be skipped while the first is running")
+	close(release)
+	bot.scheduler.shutdown()
+}
+
+func TestScheduledJob_Stop(t *testing.T) {
+	// Arrange
+	bot, timers := newSchedulerTestBot()
+	job, err := bot.Schedule("@every 1m", func(bot *Bot) {})
+	assertNoError(t, err, "Schedule should not fail")
+	bot.scheduler.start(bot)
+	<-timers.waits
+
+	// Act
+	job.Stop()
+	bot.scheduler.loops.Wait()
+
+	// Assert
+	assertTrue(t, bot.scheduler.started, "Scheduler keeps running other jobs")
+	bot.scheduler.shutdown()
+}
+
+func TestBot_Schedule_InvalidSpec(t *testing.T) {
+	// Arrange
+	bot := &Bot{}
+
+	// Act
+	job, err := bot.Schedule("every morning", func(bot *Bot) {})
+
+	// Assert
+	assertError(t, err, "Invalid spec should fail")
+	assertTrue(t, job == nil, "No job for an invalid spec")
+}
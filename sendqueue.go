@@ -0,0 +1,280 @@
+package botbooter
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/slack-go/slack"
+)
+
+var ErrSendQueueClosed = errors.New("send queue is closed")
+
+type SendQueueOptions struct {
+	MaxRetries     int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+type SendResult struct {
+	ChannelID string
+	Attempts  int
+	Err       error
+}
+
+type Delivery struct {
+	done   chan struct{}
+	result SendResult
+}
+
+func (d *Delivery) Done() <-chan struct{} {
+	return d.done
+}
+
+func (d *Delivery) Result() SendResult {
+	<-d.done
+	return d.result
+}
+
+func (d *Delivery) Wait() error {
+	return d.Result().Err
+}
+
+type sendJob struct {
+	send     func() error
+	delivery *Delivery
+}
+
+type channelQueue struct {
+	pending []sendJob
+	running bool
+}
+
+type sendQueue struct {
+	opts     SendQueueOptions
+	mu       sync.Mutex
+	channels map[string]*channelQueue
+	closed   bool
+	closing  chan struct{}
+	wg       sync.WaitGroup
+	after    func(time.Duration) <-chan time.Time
+}
+
+func newSendQueue(opts SendQueueOptions) *sendQueue {
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = 3
+	}
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = 500 * time.Millisecond
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = 30 * time.Second
+	}
+
+	return &sendQueue{
+		opts:     opts,
+		channels: map[string]*channelQueue{},
+		closing:  make(chan struct{}),
+		after:    time.After,
+	}
+}
+
+func (q *sendQueue) enqueue(channelID string, send func() error) *Delivery {
+	delivery := &Delivery{
+		done:   make(chan struct{}),
+		result: SendResult{ChannelID: channelID},
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		delivery.result.Err = ErrSendQueueClosed
+		close(delivery.done)
+		return delivery
+	}
+
+	cq, ok := q.channels[channelID]
+	if !ok {
+		cq = &channelQueue{}
+		q.channels[channelID] = cq
+	}
+	cq.pending = append(cq.pending, sendJob{send: send, delivery: delivery})
+
+	if !cq.running {
+		cq.running = true
+		q.wg.Add(1)
+		go q.drain(channelID, cq)
+	}
+
+	return delivery
+}
+
+func (q *sendQueue) drain(channelID string, cq *channelQueue) {
+	defer q.wg.Done()
+
+	for {
+		q.mu.Lock()
+		if len(cq.pending) == 0 {
+			cq.running = false
+			delete(q.channels, channelID)
+			q.mu.Unlock()
+			return
+		}
+		job := cq.pending[0]
+		cq.pending = cq.pending[1:]
+		q.mu.Unlock()
+
+		q.deliver(job)
+	}
+}
+
+func (q *sendQueue) deliver(job sendJob) {
+	q.mu.Lock()
+	closing := q.closing
+	q.mu.Unlock()
+
+	backoff := q.opts.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := job.send()
+		job.delivery.result.Attempts = attempt
+
+		if err == nil || attempt > q.opts.MaxRetries || !isTransientSendError(err) {
+			job.delivery.result.Err = err
+			close(job.delivery.done)
+			return
+		}
+
+		wait := backoff
+		if retryAfter, ok := sendRetryAfter(err); ok {
+			wait = retryAfter
+		}
+		// A closing queue does not wait for retries, so flush is not held up by rate limits.
+		select {
+		case <-closing:
+			job.delivery.result.Err = fmt.Errorf("%w: %v", ErrSendQueueClosed, err)
+			close(job.delivery.done)
+			return
+		case <-q.after(wait):
+		}
+
+		backoff *= 2
+		if backoff > q.opts.MaxBackoff {
+			backoff = q.opts.MaxBackoff
+		}
+	}
+}
+
+// flush stops accepting new messages and waits for queued ones to be sent. Messages
+// that would need a retry fail with ErrSendQueueClosed instead of waiting for it.
+// New messages are accepted again after resume.
+func (q *sendQueue) flush() {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.closing)
+	}
+	q.mu.Unlock()
+
+	q.wg.Wait()
+}
+
+func (q *sendQueue) resume() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		q.closed = false
+		q.closing = make(chan struct{})
+	}
+}
+
+func sendRetryAfter(err error) (time.Duration, bool) {
+	var slackRateLimited *slack.RateLimitedError
+	if errors.As(err, &slackRateLimited) {
+		return slackRateLimited.RetryAfter, true
+	}
+
+	var discordRateLimited *discordgo.RateLimitError
+	if errors.As(err, &discordRateLimited) && discordRateLimited.RateLimit != nil && discordRateLimited.TooManyRequests != nil {
+		return discordRateLimited.RetryAfter, true
+	}
+
+	var discordRESTError *discordgo.RESTError
+	if errors.As(err, &discordRESTError) && discordRESTError.Response != nil &&
+		discordRESTError.Response.StatusCode == http.StatusTooManyRequests {
+		seconds, parseErr := strconv.ParseFloat(discordRESTError.Response.Header.Get("Retry-After"), 64)
+		if parseErr == nil {
+			return time.Duration(seconds * float64(time.Second)), true
+		}
+	}
+
+	return 0, false
+}
+
+func isTransientSendError(err error) bool {
+	if _, ok := sendRetryAfter(err); ok {
+		return true
+	}
+
+	var retryable interface{ Retryable() bool }
+	if errors.As(err, &retryable) {
+		return retryable.Retryable()
+	}
+
+	var discordRESTError *discordgo.RESTError
+	if errors.As(err, &discordRESTError) && discordRESTError.Response != nil {
+		code := discordRESTError.Response.StatusCode
+		return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout()
+	}
+
+	return false
+}
+
+func (b *Bot) EnableSendQueue(opts SendQueueOptions) {
+	b.sendQueue = newSendQueue(opts)
+}
+
+func (b *Bot) QueueMessage(channelID string, message string) *Delivery {
+	return b.send(channelID, func() error {
+		return b.postMessage(channelID, message)
+	})
+}
+
+func (b *Bot) send(channelID string, send func() error) *Delivery {
+	instrumented := func() error {
+		err := send()
+		b.metrics.observeSend(b.platform(), err)
+		return err
+	}
+
+	if b.sendQueue != nil {
+		return b.sendQueue.enqueue(channelID, instrumented)
+	}
+
+	delivery := &Delivery{
+		done: make(chan struct{}),
+		result: SendResult{
+			ChannelID: channelID,
+			Attempts:  1,
+			Err:       instrumented(),
+		},
+	}
+	close(delivery.done)
+
+	return delivery
+}
@@ -0,0 +1,249 @@
+package botbooter
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/slack-go/slack"
+)
+
+func newTestSendQueue(opts SendQueueOptions) (*sendQueue, *[]time.Duration) {
+	queue := newSendQueue(opts)
+	var mu sync.Mutex
+	var slept []time.Duration
+	queue.after = func(d time.Duration) <-chan time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		slept = append(slept, d)
+		return immediately(d)
+	}
+	return queue, &slept
+}
+
+func TestSendQueue_PreservesPerChannelOrder(t *testing.T) {
+	// Arrange
+	queue, _ := newTestSendQueue(SendQueueOptions{})
+	var mu sync.Mutex
+	var sent []int
+	var deliveries []*Delivery
+
+	// Act
+	for i := 0; i < 20; i++ {
+		i := i
+		deliveries = append(deliveries, queue.enqueue("channel123", func() error {
+			mu.Lock()
+			defer mu.Unlock()
+			sent = append(sent, i)
+			return nil
+		}))
+	}
+	for _, delivery := range deliveries {
+		assertNoError(t, delivery.Wait(), "Delivery should succeed")
+	}
+
+	// Assert
+	assertEqual(t, len(sent), 20, "Number of sent messages")
+	for i := range sent {
+		assertEqual(t, sent[i], i, "Message order")
+	}
+}
+
+func TestSendQueue_HonorsRetryAfter(t *testing.T) {
+	// Arrange
+	queue, slept := newTestSendQueue(SendQueueOptions{MaxRetries: 2})
+	attempts := 0
+
+	// Act
+	result := queue.enqueue("channel123", func() error {
+		attempts++
+		if attempts == 1 {
+			return &slack.RateLimitedError{RetryAfter: 3 * time.Second}
+		}
+		return nil
+	}).Result()
+
+	// Assert
+	assertNoError(t, result.Err, "Delivery should succeed after rate limit")
+	assertEqual(t, result.Attempts, 2, "Number of attempts")
+	assertEqual(t, len(*slept), 1, "Number of waits")
+	assertEqual(t, (*slept)[0], 3*time.Second, "Wait should honor Retry-After")
+}
+
+func TestSendQueue_BacksOffOnTransientErrors(t *testing.T) {
+	// Arrange
+	queue, slept := newTestSendQueue(SendQueueOptions{
+		MaxRetries:     3,
+		InitialBackoff: time.Second,
+		MaxBackoff:     3 * time.Second,
+	})
+	transient := &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusBadGateway}}
+
+	// Act
+	result := queue.enqueue("channel123", func() error {
+		return transient
+	}).Result()
+
+	// Assert
+	assertError(t, result.Err, "Delivery should fail after retries are exhausted")
+	assertEqual(t, result.Attempts, 4, "Number of attempts")
+	assertEqual(t, len(*slept), 3, "Number of waits")
+	assertEqual(t, (*slept)[0], time.Second, "First backoff")
+	assertEqual(t, (*slept)[1], 2*time.Second, "Second backoff")
+	assertEqual(t, (*slept)[2], 3*time.Second, "Backoff should be capped")
+}
+
+func TestSendQueue_DoesNotRetryPermanentErrors(t *testing.T) {
+	// Arrange
+	queue, slept := newTestSendQueue(SendQueueOptions{})
+	permanent := errors.New("channel_not_found")
+
+	// Act
+	result := queue.enqueue("channel123", func() error {
+		return permanent
+	}).Result()
+
+	// Assert
+	assertEqual(t, result.Err, permanent, "Delivery error")
+	assertEqual(t, result.Attempts, 1, "Number of attempts")
+	assertEqual(t, len(*slept), 0, "Number of waits")
+}
+
+func TestIsTransientSendError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"slack rate limit", &slack.RateLimitedError{RetryAfter: time.Second}, true},
+		{"slack server error", slack.StatusCodeError{Code: http.StatusServiceUnavailable}, true},
+		{"slack client error", slack.StatusCodeError{Code: http.StatusNotFound}, false},
+		{"discord too many requests", &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"1.5"}}}}, true},
+		{"discord rate limit", &discordgo.RateLimitError{RateLimit: &discordgo.RateLimit{TooManyRequests: &discordgo.TooManyRequests{RetryAfter: time.Second}}}, true},
+		{"discord forbidden", &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusForbidden}}, false},
+		{"plain error", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertEqual(t, isTransientSendError(tt.err), tt.want, "isTransientSendError result")
+		})
+	}
+}
+
+func TestSendRetryAfter_DiscordHeader(t *testing.T) {
+	// Arrange
+	err := &discordgo.RESTError{Response: &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Retry-After": {"1.5"}},
+	}}
+
+	// Act
+	wait, ok := sendRetryAfter(err)
+
+	// Assert
+	assertTrue(t, ok, "Retry-After should be detected")
+	assertEqual(t, wait, 1500*time.Millisecond, "Retry-After duration")
+}
+
+func TestSendRetryAfter_DiscordRateLimitError(t *testing.T) {
+	// Arrange
+	err := &discordgo.RateLimitError{RateLimit: &discordgo.RateLimit{
+		TooManyRequests: &discordgo.TooManyRequests{RetryAfter: 2 * time.Second},
+	}}
+
+	// Act
+	wait, ok := sendRetryAfter(err)
+
+	// Assert
+	assertTrue(t, ok, "Rate limit error should be detected")
+	assertEqual(t, wait, 2*time.Second, "Retry-After duration")
+}
+
+func TestSendQueue_FlushStopsAcceptingUntilResume(t *testing.T) {
+	// Arrange
+	queue, _ := newTestSendQueue(SendQueueOptions{})
+	release := make(chan struct{})
+	pending := queue.enqueue("channel123", func() error {
+		<-release
+		return nil
+	})
+
+	// Act
+	flushed := make(chan struct{})
+	go func() {
+		queue.flush()
+		close(flushed)
+	}()
+	for {
+		queue.mu.Lock()
+		closed := queue.closed
+		queue.mu.Unlock()
+		if closed {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	rejected := queue.enqueue("channel123", func() error { return nil }).Wait()
+	close(release)
+	<-flushed
+	queue.resume()
+	accepted := queue.enqueue("channel123", func() error { return nil }).Wait()
+
+	// Assert
+	assertNoError(t, pending.Wait(), "Queued message should be delivered by flush")
+	assertTrue(t, errors.Is(rejected, ErrSendQueueClosed), "Messages during flush should be rejected")
+	assertNoError(t, accepted, "Messages after resume should be accepted")
+}
+
+func TestSendQueue_FlushInterruptsRetries(t *testing.T) {
+	// Arrange
+	queue := newSendQueue(SendQueueOptions{MaxRetries: 5})
+	waiting := make(chan struct{})
+	queue.after = func(time.Duration) <-chan time.Time {
+		close(waiting)
+		return nil
+	}
+	delivery := queue.enqueue("channel123", func() error {
+		return &slack.RateLimitedError{RetryAfter: time.Hour}
+	})
+	<-waiting
+
+	// Act
+	queue.flush()
+	result := delivery.Result()
+
+	// Assert
+	assertTrue(t, errors.Is(result.Err, ErrSendQueueClosed), "Retrying message should fail when the queue closes")
+	assertEqual(t, result.Attempts, 1, "Number of attempts")
+}
+
+func TestBot_QueueMessage(t *testing.T) {
+	t.Run("WithoutQueue", func(t *testing.T) {
+		// Arrange
+		bot := &Bot{BotType: BotType(999)}
+
+		// Act
+		result := bot.QueueMessage("channel123", "test message").Result()
+
+		// Assert
+		assertError(t, result.Err, "Delivery with unknown bot type should fail")
+		assertEqual(t, result.Attempts, 1, "Number of attempts")
+	})
+
+	t.Run("WithQueue", func(t *testing.T) {
+		// Arrange
+		bot := &Bot{BotType: BotType(999)}
+		bot.EnableSendQueue(SendQueueOptions{})
+
+		// Act
+		err := bot.SendMessage("channel123", "test message")
+
+		// Assert
+		assertError(t, err, "SendMessage with unknown bot type should fail")
+		assertEqual(t, err.Error(), "unknown bot type", "Error message for unknown bot type")
+	})
+}
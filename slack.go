@@ -2,29 +2,20 @@ package botbooter
 
 import (
 	"context"
+	"strings"
 
-	"github.com/slack-go/slack"
 	"github.com/slack-go/slack/slackevents"
 	"github.com/slack-go/slack/socketmode"
 )
 
+// InitAsSlackBot does not validate its tokens; NewSlackBot does.
 func InitAsSlackBot(appToken, botToken string) *Bot {
-	client := slack.New(
-		botToken,
-		slack.OptionAppLevelToken(appToken),
-	)
-
-	socketClient := socketmode.New(client)
-
-	return &Bot{
-		SlackClient:           client,
-		SlackSocketClient:     socketClient,
-		Commands:              []Command{},
-		UnknownCommandHandler: nil,
-	}
+	return newSlackBot(&botConfig{slackAppToken: appToken, slackBotToken: botToken})
 }
 
 func (b *Bot) handleSlackSocketEvent(evt socketmode.Event) {
+	b.recordEvent()
+
 	switch evt.Type {
 	case socketmode.EventTypeEventsAPI:
 		payload, ok := evt.Data.(slackevents.EventsAPIEvent)
@@ -33,13 +24,32 @@ func (b *Bot) handleSlackSocketEvent(evt socketmode.Event) {
 		}
 		b.SlackSocketClient.Ack(*evt.Request)
 		b.handleSlackEventsApi(payload)
+	case socketmode.EventTypeInteractive:
+		b.handleSlackInteractive(evt)
+	case socketmode.EventTypeConnected:
+		b.markConnected()
+	case socketmode.EventTypeDisconnect:
+		// Slack asks clients to reconnect periodically; socket mode does so on its own.
+		b.markDisconnected(nil)
+		b.markReconnecting(1, 0)
 	}
 }
 
 func (b *Bot) connectSlack() error {
+	auth, err := b.SlackClient.AuthTest()
+	if err != nil {
+		return err
+	}
+	b.slackBotUserID = auth.UserID
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	b.connMu.Lock()
+	b.slackCancel = cancel
+	b.connMu.Unlock()
+	b.scheduler.start(b)
+
 	go func(ctx context.Context) {
 		for {
 			select {
@@ -51,7 +61,11 @@ func (b *Bot) connectSlack() error {
 		}
 	}(ctx)
 
-	err := b.SlackSocketClient.Run()
+	err = b.SlackSocketClient.RunContext(ctx)
+	if ctx.Err() != nil {
+		// Cancelled by disconnectSlack.
+		return nil
+	}
 	return err
 }
 
@@ -63,7 +77,11 @@ func isSlackBotMessage(event slackevents.EventsAPIEvent) bool {
 	switch ev := data.(type) {
 	case *slackevents.MessageEvent:
 		// if bot id is not empty then it is a bot message
-		if ev.BotID != "" || ev.SubType == "bot_message" || ev.Text == "" {
+		if ev.BotID != "" || ev.SubType == "bot_message" {
+			return true
+		}
+		// Uploads without a comment have no text but are still user messages.
+		if ev.Text == "" && len(ev.Files) == 0 && ev.SubType != "file_share" {
 			return true
 		}
 	case *slackevents.AppMentionEvent:
@@ -90,8 +108,19 @@ func isSlackBotMessage(event slackevents.EventsAPIEvent) bool {
 }
 
 func (b *Bot) handleSlackEventsApi(e slackevents.EventsAPIEvent) {
+	if ev, ok := e.InnerEvent.Data.(*slackevents.MessageEvent); ok {
+		switch ev.SubType {
+		case "message_changed":
+			b.handleSlackMessageChanged(ev)
+			return
+		case "message_deleted":
+			b.handleSlackMessageDeleted(ev)
+			return
+		}
+	}
 
 	if isSlackBotMessage(e) {
+		b.logger().Debug("Slack event ignored, sent by a bot", "type", e.InnerEvent.Type)
 		return
 	}
 
@@ -102,24 +131,73 @@ func (b *Bot) handleSlackEventsApi(e slackevents.EventsAPIEvent) {
 		message := &Message{
 			UserID:    msg.User,
 			ChannelID: msg.Channel,
+			MessageID: msg.TimeStamp,
+			ThreadID:  msg.ThreadTimeStamp,
 			Content:   msg.Text,
+			IsDirect:  msg.ChannelType == "im",
 			SlackData: msg,
 		}
 
-		b.handleMessageWithCommand(message)
+		// In mention-only mode channel mentions arrive as app_mention events instead.
+		if b.MentionOnly && !message.IsDirect {
+			b.logger().Debug("Slack message ignored, waiting for app_mention", "channel", message.ChannelID)
+			return
+		}
+
+		b.dispatchIncoming(message)
+	case *slackevents.AppMentionEvent:
+		if !b.MentionOnly {
+			// The same text also arrives as a message event.
+			return
+		}
+
+		mention := e.InnerEvent.Data.(*slackevents.AppMentionEvent)
+		msg := &slackevents.MessageEvent{
+			Type:            mention.Type,
+			User:            mention.User,
+			Text:            mention.Text,
+			TimeStamp:       mention.TimeStamp,
+			ThreadTimeStamp: mention.ThreadTimeStamp,
+			Channel:         mention.Channel,
+			EventTimeStamp:  mention.EventTimeStamp,
+			UserTeam:        mention.UserTeam,
+			SourceTeam:      mention.SourceTeam,
+		}
+
+		b.dispatchIncoming(&Message{
+			UserID:      msg.User,
+			ChannelID:   msg.Channel,
+			MessageID:   msg.TimeStamp,
+			ThreadID:    msg.ThreadTimeStamp,
+			Content:     msg.Text,
+			MentionsBot: true,
+			SlackData:   msg,
+		})
+	case *slackevents.ReactionAddedEvent:
+		b.handleSlackReactionAdded(e.InnerEvent.Data.(*slackevents.ReactionAddedEvent))
 	}
 }
 
 func (b *Bot) disconnectSlack() error {
-	close(b.SlackSocketClient.Events)
+	b.connMu.Lock()
+	defer b.connMu.Unlock()
+
+	if b.slackCancel != nil {
+		b.slackCancel()
+		b.slackCancel = nil
+	}
 	return nil
 }
 func getAttachmentsFromSlackMessage(m *slackevents.MessageEvent) []Attachment {
 	var attachments []Attachment
 
 	for _, file := range m.Files {
-		isImage := file.Mimetype != "" && file.Mimetype[:5] == "image"
+		isImage := strings.HasPrefix(file.Mimetype, "image/")
 		attachments = append(attachments, Attachment{
+			ID:        file.ID,
+			Filename:  file.Name,
+			MimeType:  file.Mimetype,
+			Size:      int64(file.Size),
 			IsImage:   isImage,
 			URL:       file.URLPrivate,
 			ExtraData: file,
@@ -61,6 +61,18 @@ func TestIsSlackBotMessage(t *testing.T) {
 			},
 			expectedIsBotMessage: true,
 		},
+		{
+			name: "file upload without comment",
+			event: slackevents.EventsAPIEvent{
+				InnerEvent: slackevents.EventsAPIInnerEvent{
+					Data: &slackevents.MessageEvent{
+						SubType: "file_share",
+						Files:   []slackevents.File{{Mimetype: "image/png"}},
+					},
+				},
+			},
+			expectedIsBotMessage: false,
+		},
 		{
 			name: "user message",
 			event: slackevents.EventsAPIEvent{
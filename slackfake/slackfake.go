@@ -0,0 +1,559 @@
+// Package slackfake runs an in-process fake of the Slack Web API and Socket Mode
+// websocket for end-to-end tests of botbooter Slack bots.
+package slackfake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/lao/botbooter"
+	"github.com/slack-go/slack"
+)
+
+const (
+	AppToken = "xapp-fake"
+	BotToken = "xoxb-fake"
+
+	pingInterval = 5 * time.Second
+)
+
+type Call struct {
+	Method string
+	Params url.Values
+	// Files holds the content of files shared by files.completeUploadExternal, by filename.
+	Files map[string][]byte
+}
+
+type upload struct {
+	name     string
+	content  []byte
+	uploaded bool
+}
+
+type Server struct {
+	BotUserID string
+	TeamID    string
+
+	http      *httptest.Server
+	upgrader  websocket.Upgrader
+	mu        sync.Mutex
+	changed   *sync.Cond
+	calls     []Call
+	conn      *websocket.Conn
+	writeMu   sync.Mutex
+	users     map[string]slack.User
+	channels  map[string]slack.Channel
+	files     map[string][]byte
+	uploads   map[string]*upload
+	acks      map[string]bool
+	sequence  int
+	closed    bool
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func NewServer() *Server {
+	s := &Server{
+		BotUserID: "UBOT",
+		TeamID:    "TFAKE",
+		users:     map[string]slack.User{},
+		channels:  map[string]slack.Channel{},
+		files:     map[string][]byte{},
+		uploads:   map[string]*upload{},
+		acks:      map[string]bool{},
+		done:      make(chan struct{}),
+	}
+	// The Socket Mode client sends a Slack origin, which would fail the default same-origin check.
+	s.upgrader.CheckOrigin = func(*http.Request) bool { return true }
+	s.changed = sync.NewCond(&s.mu)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/", s.handleAPI)
+	mux.HandleFunc("/socket", s.handleSocket)
+	mux.HandleFunc("/files/", s.handleFile)
+	mux.HandleFunc("/upload/", s.handleUpload)
+	s.http = httptest.NewServer(mux)
+	return s
+}
+
+func (s *Server) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+
+		s.mu.Lock()
+		s.closed = true
+		conn := s.conn
+		s.changed.Broadcast()
+		s.mu.Unlock()
+
+		if conn != nil {
+			conn.Close()
+		}
+		s.http.Close()
+	})
+}
+
+func (s *Server) URL() string {
+	return s.http.URL
+}
+
+func (s *Server) APIURL() string {
+	return s.http.URL + "/api/"
+}
+
+// Options configures NewSlackBot to talk to the fake server.
+func (s *Server) Options() []botbooter.Option {
+	return []botbooter.Option{
+		botbooter.WithSlackTokens(AppToken, BotToken),
+		botbooter.WithAPIURL(s.APIURL()),
+	}
+}
+
+// Attach points a bot created with InitAsSlackBot at the fake server, switching it to
+// the fake's tokens.
+func (s *Server) Attach(bot *botbooter.Bot) error {
+	return bot.Reconfigure(s.Options()...)
+}
+
+func (s *Server) AddUser(user slack.User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[user.ID] = user
+}
+
+func (s *Server) AddChannel(channel slack.Channel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.channels[channel.ID] = channel
+}
+
+// AddFile serves content as a private file and returns its URL.
+func (s *Server) AddFile(name string, content []byte) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sequence++
+	path := fmt.Sprintf("/files/F%d/%s", s.sequence, url.PathEscape(name))
+	s.files[path] = content
+	return s.http.URL + path
+}
+
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Call(nil), s.calls...)
+}
+
+func (s *Server) CallsTo(method string) []Call {
+	var calls []Call
+	for _, call := range s.Calls() {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// WaitForCall waits until method has been called n times and returns the nth call.
+func (s *Server) WaitForCall(method string, n int, timeout time.Duration) (Call, error) {
+	var call Call
+	err := s.waitFor(timeout, func() bool {
+		count := 0
+		for _, c := range s.calls {
+			if c.Method == method {
+				count++
+				if count == n {
+					call = c
+					return true
+				}
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return Call{}, fmt.Errorf("waiting for call %d to %s: %w", n, method, err)
+	}
+	return call, nil
+}
+
+func (s *Server) WaitForConnection(timeout time.Duration) error {
+	return s.waitFor(timeout, func() bool {
+		return s.conn != nil
+	})
+}
+
+func (s *Server) WaitForAck(envelopeID string, timeout time.Duration) error {
+	return s.waitFor(timeout, func() bool {
+		return s.acks[envelopeID]
+	})
+}
+
+func (s *Server) waitFor(timeout time.Duration, condition func() bool) error {
+	timer := time.AfterFunc(timeout, func() {
+		s.mu.Lock()
+		s.changed.Broadcast()
+		s.mu.Unlock()
+	})
+	defer timer.Stop()
+
+	deadline := time.Now().Add(timeout)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for !condition() {
+		if s.closed {
+			return errors.New("server closed")
+		}
+		if !time.Now().Before(deadline) {
+			return errors.New("timed out")
+		}
+		s.changed.Wait()
+	}
+	return nil
+}
+
+// SendMessage delivers a message event as if userID had typed text in channelID and
+// returns the message timestamp.
+func (s *Server) SendMessage(channelID, userID, text string) (string, error) {
+	ts := s.nextTimestamp()
+	channelType := "channel"
+	if strings.HasPrefix(channelID, "D") {
+		channelType = "im"
+	}
+
+	_, err := s.PushEvent(map[string]interface{}{
+		"type":         "message",
+		"channel":      channelID,
+		"user":         userID,
+		"text":         text,
+		"ts":           ts,
+		"event_ts":     ts,
+		"channel_type": channelType,
+	})
+	return ts, err
+}
+
+// PushEvent wraps event in an Events API envelope, sends it over the socket and
+// returns the envelope ID.
+func (s *Server) PushEvent(event map[string]interface{}) (string, error) {
+	envelopeID := s.nextID("envelope-")
+	eventID := s.nextID("Ev")
+
+	err := s.writeJSON(map[string]interface{}{
+		"envelope_id":              envelopeID,
+		"type":                     "events_api",
+		"accepts_response_payload": false,
+		"payload": map[string]interface{}{
+			"token":      "fake",
+			"team_id":    s.TeamID,
+			"api_app_id": "AFAKE",
+			"type":       "event_callback",
+			"event_id":   eventID,
+			"event_time": time.Now().Unix(),
+			"event":      event,
+		},
+	})
+	return envelopeID, err
+}
+
+func (s *Server) writeJSON(v interface{}) error {
+	s.mu.Lock()
+	conn := s.conn
+	s.mu.Unlock()
+	if conn == nil {
+		return errors.New("no socket mode connection")
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return conn.WriteJSON(v)
+}
+
+func (s *Server) nextID(prefix string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sequence++
+	return fmt.Sprintf("%s%d", prefix, s.sequence)
+}
+
+func (s *Server) nextTimestamp() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sequence++
+	return fmt.Sprintf("%d.%06d", time.Now().Unix(), s.sequence)
+}
+
+func (s *Server) handleSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	previous := s.conn
+	s.conn = conn
+	s.changed.Broadcast()
+	s.mu.Unlock()
+	if previous != nil {
+		previous.Close()
+	}
+
+	s.writeJSON(map[string]interface{}{
+		"type":            "hello",
+		"num_connections": 1,
+		"connection_info": map[string]string{"app_id": "AFAKE"},
+	})
+
+	go s.ping(conn)
+	s.readAcks(conn)
+
+	s.mu.Lock()
+	if s.conn == conn {
+		s.conn = nil
+	}
+	s.changed.Broadcast()
+	s.mu.Unlock()
+}
+
+func (s *Server) ping(conn *websocket.Conn) {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.writeMu.Lock()
+			err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(time.Second))
+			s.writeMu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *Server) readAcks(conn *websocket.Conn) {
+	for {
+		var ack struct {
+			EnvelopeID string `json:"envelope_id"`
+		}
+		if err := conn.ReadJSON(&ack); err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.acks[ack.EnvelopeID] = true
+		s.changed.Broadcast()
+		s.mu.Unlock()
+	}
+}
+
+func (s *Server) handleFile(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+BotToken {
+		http.Error(w, "not authorized", http.StatusUnauthorized)
+		return
+	}
+
+	s.mu.Lock()
+	content, ok := s.files[r.URL.Path]
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Write(content)
+}
+
+// handleUpload receives file content at an upload URL from files.getUploadURLExternal.
+func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+BotToken {
+		http.Error(w, "not authorized", http.StatusUnauthorized)
+		return
+	}
+
+	f, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.uploads[strings.TrimPrefix(r.URL.Path, "/upload/")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	u.content = content
+	u.uploaded = true
+	fmt.Fprintf(w, "OK - %d", len(content))
+}
+
+func (s *Server) handleAPI(w http.ResponseWriter, r *http.Request) {
+	call, err := parseCall(r)
+	if err != nil {
+		writeJSON(w, map[string]interface{}{"ok": false, "error": "invalid_form_data"})
+		return
+	}
+
+	s.mu.Lock()
+	if call.Method == "files.completeUploadExternal" {
+		call.Files = s.uploadedFiles(call.Params.Get("files"))
+	}
+	s.calls = append(s.calls, call)
+	s.changed.Broadcast()
+	s.mu.Unlock()
+
+	writeJSON(w, s.respond(call))
+}
+
+func parseCall(r *http.Request) (Call, error) {
+	call := Call{Method: strings.TrimPrefix(r.URL.Path, "/api/")}
+	if err := r.ParseForm(); err != nil {
+		return call, err
+	}
+	call.Params = r.Form
+	return call, nil
+}
+
+type fileSummary struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// uploadedFiles returns the content of the uploaded files listed in a
+// files.completeUploadExternal call. s.mu must be held.
+func (s *Server) uploadedFiles(files string) map[string][]byte {
+	var summaries []fileSummary
+	json.Unmarshal([]byte(files), &summaries)
+
+	contents := map[string][]byte{}
+	for _, summary := range summaries {
+		if u, ok := s.uploads[summary.ID]; ok && u.uploaded {
+			contents[u.name] = u.content
+		}
+	}
+	return contents
+}
+
+func (s *Server) respond(call Call) map[string]interface{} {
+	params := call.Params
+	switch call.Method {
+	case "auth.test":
+		return map[string]interface{}{
+			"ok":      true,
+			"url":     s.http.URL + "/",
+			"team":    "Fake",
+			"user":    "bot",
+			"team_id": s.TeamID,
+			"user_id": s.BotUserID,
+			"bot_id":  "BFAKE",
+		}
+	case "apps.connections.open":
+		return map[string]interface{}{
+			"ok":  true,
+			"url": "ws" + strings.TrimPrefix(s.http.URL, "http") + "/socket",
+		}
+	case "chat.postMessage":
+		ts := s.nextTimestamp()
+		return map[string]interface{}{
+			"ok":      true,
+			"channel": params.Get("channel"),
+			"ts":      ts,
+			"message": map[string]interface{}{
+				"type":      "message",
+				"user":      s.BotUserID,
+				"text":      params.Get("text"),
+				"ts":        ts,
+				"thread_ts": params.Get("thread_ts"),
+			},
+		}
+	case "chat.update":
+		return map[string]interface{}{
+			"ok":      true,
+			"channel": params.Get("channel"),
+			"ts":      params.Get("ts"),
+			"text":    params.Get("text"),
+		}
+	case "chat.delete":
+		return map[string]interface{}{"ok": true, "channel": params.Get("channel"), "ts": params.Get("ts")}
+	case "reactions.add", "reactions.remove":
+		return map[string]interface{}{"ok": true}
+	case "users.info":
+		s.mu.Lock()
+		user, ok := s.users[params.Get("user")]
+		s.mu.Unlock()
+		if !ok {
+			return map[string]interface{}{"ok": false, "error": "user_not_found"}
+		}
+		return map[string]interface{}{"ok": true, "user": user}
+	case "conversations.info":
+		s.mu.Lock()
+		channel, ok := s.channels[params.Get("channel")]
+		s.mu.Unlock()
+		if !ok {
+			return map[string]interface{}{"ok": false, "error": "channel_not_found"}
+		}
+		return map[string]interface{}{"ok": true, "channel": channel}
+	case "conversations.open":
+		return map[string]interface{}{
+			"ok":      true,
+			"channel": map[string]interface{}{"id": "D" + strings.TrimPrefix(params.Get("users"), "U")},
+		}
+	case "files.getUploadURLExternal":
+		if params.Get("filename") == "" || params.Get("length") == "" {
+			return map[string]interface{}{"ok": false, "error": "invalid_arguments"}
+		}
+		id := s.nextID("F")
+		s.mu.Lock()
+		s.uploads[id] = &upload{name: params.Get("filename")}
+		s.mu.Unlock()
+		return map[string]interface{}{
+			"ok":         true,
+			"upload_url": s.http.URL + "/upload/" + id,
+			"file_id":    id,
+		}
+	case "files.completeUploadExternal":
+		var summaries []fileSummary
+		if err := json.Unmarshal([]byte(params.Get("files")), &summaries); err != nil || len(summaries) == 0 {
+			return map[string]interface{}{"ok": false, "error": "invalid_arguments"}
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, summary := range summaries {
+			if u, ok := s.uploads[summary.ID]; !ok || !u.uploaded {
+				return map[string]interface{}{"ok": false, "error": "file_not_found"}
+			}
+		}
+		return map[string]interface{}{"ok": true, "files": summaries}
+	default:
+		return map[string]interface{}{"ok": false, "error": "unknown_method"}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
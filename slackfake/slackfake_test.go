@@ -0,0 +1,350 @@
+package slackfake
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lao/botbooter"
+	"github.com/slack-go/slack"
+)
+
+const timeout = 5 * time.Second
+
+func connect(t *testing.T, server *Server, bot *botbooter.Bot) {
+	t.Helper()
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- bot.Connect()
+	}()
+	t.Cleanup(func() {
+		bot.Disconnect()
+		select {
+		case <-errs:
+		case <-time.After(timeout):
+			t.Error("Connect did not return after Disconnect")
+		}
+	})
+
+	if err := server.WaitForConnection(timeout); err != nil {
+		t.Fatalf("bot did not open a Socket Mode connection: %v", err)
+	}
+}
+
+func TestEndToEnd_EchoCommand(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+
+	bot := botbooter.InitAsSlackBot("xapp-test", "xoxb-test")
+	if err := server.Attach(bot); err != nil {
+		t.Fatal(err)
+	}
+	bot.AddHandler(botbooter.Command{
+		Pattern: "^echo ",
+		Handler: func(bot *botbooter.Bot, message *botbooter.Message) {
+			bot.SendMessage(message.ChannelID, "You said: "+strings.TrimPrefix(message.Content, "echo "))
+		},
+	})
+	connect(t, server, bot)
+
+	// Act
+	_, err := server.SendMessage("C123", "U123", "echo hello")
+	if err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	call, err := server.WaitForCall("chat.postMessage", 1, timeout)
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := call.Params.Get("channel"); got != "C123" {
+		t.Errorf("channel = %q, want %q", got, "C123")
+	}
+	if got := call.Params.Get("text"); got != "You said: hello" {
+		t.Errorf("text = %q, want %q", got, "You said: hello")
+	}
+	if len(server.CallsTo("auth.test")) != 1 {
+		t.Errorf("expected auth.test to be called once")
+	}
+}
+
+func TestEndToEnd_AcksEvents(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+
+	bot := botbooter.InitAsSlackBot("xapp-test", "xoxb-test")
+	if err := server.Attach(bot); err != nil {
+		t.Fatal(err)
+	}
+	connect(t, server, bot)
+
+	// Act
+	envelopeID, err := server.PushEvent(map[string]interface{}{
+		"type":    "message",
+		"channel": "C123",
+		"user":    "U123",
+		"text":    "nobody handles this",
+		"ts":      "1700000000.000100",
+	})
+	if err != nil {
+		t.Fatalf("PushEvent failed: %v", err)
+	}
+
+	// Assert
+	if err := server.WaitForAck(envelopeID, timeout); err != nil {
+		t.Errorf("event was not acknowledged: %v", err)
+	}
+}
+
+func TestEndToEnd_ConnectionState(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+
+	connected := make(chan struct{}, 1)
+	bot := botbooter.InitAsSlackBot("xapp-test", "xoxb-test")
+	if err := server.Attach(bot); err != nil {
+		t.Fatal(err)
+	}
+	bot.OnConnect(func(bot *botbooter.Bot) {
+		connected <- struct{}{}
+	})
+
+	// Act
+	connect(t, server, bot)
+
+	// Assert
+	select {
+	case <-connected:
+	case <-time.After(timeout):
+		t.Fatal("OnConnect was not called")
+	}
+	if bot.State() != botbooter.Connected {
+		t.Errorf("State() = %v, want %v", bot.State(), botbooter.Connected)
+	}
+}
+
+func TestEndToEnd_Run(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+
+	bot := botbooter.InitAsSlackBot("xapp-test", "xoxb-test")
+	if err := server.Attach(bot); err != nil {
+		t.Fatal(err)
+	}
+	bot.AddHandler(botbooter.Command{
+		Pattern: "^ping$",
+		Handler: func(bot *botbooter.Bot, message *botbooter.Message) {
+			bot.ReplyInThread(message, "pong")
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- bot.Run(ctx)
+	}()
+	if err := server.WaitForConnection(timeout); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	ts, err := server.SendMessage("C123", "U123", "ping")
+	if err != nil {
+		t.Fatal(err)
+	}
+	call, err := server.WaitForCall("chat.postMessage", 1, timeout)
+	cancel()
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := call.Params.Get("thread_ts"); got != ts {
+		t.Errorf("thread_ts = %q, want %q", got, ts)
+	}
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		t.Fatal("Run did not return after the context was cancelled")
+	}
+}
+
+func TestGetUser(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+	server.AddUser(slack.User{
+		ID:       "U123",
+		Name:     "ada",
+		RealName: "Ada Lovelace",
+		TZ:       "Europe/London",
+		Profile:  slack.UserProfile{DisplayName: "ada", Email: "ada@example.com"},
+	})
+
+	bot, err := botbooter.NewSlackBot(server.Options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	user, err := bot.GetUser("U123")
+	_, missingErr := bot.GetUser("U404")
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.RealName != "Ada Lovelace" || user.Email != "ada@example.com" {
+		t.Errorf("unexpected user profile: %+v", user)
+	}
+	if missingErr == nil {
+		t.Error("expected an error for an unknown user")
+	}
+	if got := len(server.CallsTo("users.info")); got != 2 {
+		t.Errorf("users.info calls = %d, want 2", got)
+	}
+}
+
+func TestGetChannel(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+	channel := slack.Channel{}
+	channel.ID = "C123"
+	channel.Name = "general"
+	channel.Topic.Value = "Announcements"
+	server.AddChannel(channel)
+
+	bot, err := botbooter.NewSlackBot(server.Options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	info, err := bot.GetChannel("C123")
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Name != "general" || info.Topic != "Announcements" {
+		t.Errorf("unexpected channel: %+v", info)
+	}
+}
+
+func TestSendFile(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+
+	bot, err := botbooter.NewSlackBot(server.Options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err = bot.SendFile("C123", "report.csv", strings.NewReader("a,b\n1,2\n"), botbooter.FileOptions{
+		Caption:  "Daily report",
+		ThreadID: "1700000000.000100",
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(server.CallsTo("files.getUploadURLExternal")); got != 1 {
+		t.Errorf("files.getUploadURLExternal calls = %d, want 1", got)
+	}
+	calls := server.CallsTo("files.completeUploadExternal")
+	if len(calls) != 1 {
+		t.Fatalf("files.completeUploadExternal calls = %d, want 1", len(calls))
+	}
+	if got := calls[0].Params.Get("channel_id"); got != "C123" {
+		t.Errorf("channel_id = %q, want %q", got, "C123")
+	}
+	if got := calls[0].Params.Get("initial_comment"); got != "Daily report" {
+		t.Errorf("initial_comment = %q, want %q", got, "Daily report")
+	}
+	if got := calls[0].Params.Get("thread_ts"); got != "1700000000.000100" {
+		t.Errorf("thread_ts = %q, want %q", got, "1700000000.000100")
+	}
+	if got := string(calls[0].Files["report.csv"]); got != "a,b\n1,2\n" {
+		t.Errorf("uploaded content = %q", got)
+	}
+}
+
+func TestDownloadAttachment(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+	fileURL := server.AddFile("chart.png", []byte("png bytes"))
+
+	bot, err := botbooter.NewSlackBot(server.Options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	r, err := bot.DownloadAttachment(context.Background(), botbooter.Attachment{URL: fileURL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	content, err := io.ReadAll(r)
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(content, []byte("png bytes")) {
+		t.Errorf("downloaded content = %q", content)
+	}
+}
+
+func TestAttach_DownloadAttachment(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+	fileURL := server.AddFile("chart.png", []byte("png bytes"))
+
+	bot := botbooter.InitAsSlackBot("xapp-test", "xoxb-test")
+	if err := server.Attach(bot); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	r, err := bot.DownloadAttachment(context.Background(), botbooter.Attachment{URL: fileURL})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("attached bot should download with the fake's token: %v", err)
+	}
+	r.Close()
+}
+
+func TestUnknownMethod(t *testing.T) {
+	// Arrange
+	server := NewServer()
+	defer server.Close()
+	client := slack.New(BotToken, slack.OptionAPIURL(server.APIURL()))
+
+	// Act
+	_, err := client.GetTeamInfo()
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), "unknown_method") {
+		t.Errorf("expected unknown_method error, got %v", err)
+	}
+	if len(server.CallsTo("team.info")) != 1 {
+		t.Error("expected team.info call to be recorded")
+	}
+}
@@ -0,0 +1,113 @@
+package botbooter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/slack-go/slack"
+)
+
+const (
+	discordThreadNameLength      = 50
+	discordThreadArchiveDuration = 1440
+)
+
+func (b *Bot) ReplyInThread(message *Message, text string) error {
+	return b.send(message.ChannelID, func() error {
+		_, _, err := b.postThreadReply(message, text)
+		return err
+	}).Wait()
+}
+
+func (b *Bot) postReply(message *Message, text string) (string, string, error) {
+	if b.ReplyInThreadByDefault {
+		return b.postThreadReply(message, text)
+	}
+
+	messageID, err := b.postMessageWithID(message.ChannelID, text)
+	return message.ChannelID, messageID, err
+}
+
+func (b *Bot) postThreadReply(message *Message, text string) (string, string, error) {
+	switch b.BotType {
+	case SlackBotType:
+		threadTS := message.ThreadID
+		if threadTS == "" {
+			threadTS = message.MessageID
+		}
+		_, ts, err := b.SlackClient.PostMessage(
+			message.ChannelID,
+			slack.MsgOptionText(text, false),
+			slack.MsgOptionTS(threadTS),
+		)
+		return message.ChannelID, ts, err
+	case DiscordBotType:
+		threadID, err := b.discordThreadFor(message)
+		if err != nil {
+			return "", "", err
+		}
+		messageID, err := b.postMessageWithID(threadID, text)
+		return threadID, messageID, err
+	default:
+		return "", "", fmt.Errorf("unknown bot type")
+	}
+}
+
+func (b *Bot) discordThreadFor(message *Message) (string, error) {
+	if message.ThreadID != "" {
+		return message.ThreadID, nil
+	}
+
+	// Threads cannot be started in DMs, so the reply goes to the channel itself.
+	if message.IsDirect {
+		return message.ChannelID, nil
+	}
+
+	// Discord allows one thread per message, and that thread has the message's ID.
+	threadID := message.MessageID
+	thread, err := b.DiscordSession.MessageThreadStart(
+		message.ChannelID,
+		message.MessageID,
+		discordThreadName(message.Content),
+		discordThreadArchiveDuration,
+	)
+	var restErr *discordgo.RESTError
+	switch {
+	case err == nil:
+		threadID = thread.ID
+	case errors.As(err, &restErr) && restErr.Message != nil &&
+		restErr.Message.Code == discordgo.ErrCodeThreadAlreadyCreatedForThisMessage:
+	default:
+		return "", err
+	}
+
+	message.ThreadID = threadID
+	return threadID, nil
+}
+
+func discordThreadName(content string) string {
+	name := strings.TrimSpace(strings.SplitN(content, "\n", 2)[0])
+	if name == "" {
+		return "Thread"
+	}
+
+	runes := []rune(name)
+	if len(runes) > discordThreadNameLength {
+		return string(runes[:discordThreadNameLength])
+	}
+	return name
+}
+
+func discordThreadID(s *discordgo.Session, channelID string) string {
+	if s == nil || s.State == nil {
+		return ""
+	}
+
+	channel, err := s.State.Channel(channelID)
+	if err != nil || !channel.IsThread() {
+		return ""
+	}
+	return channel.ID
+}
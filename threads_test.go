@@ -0,0 +1,176 @@
+package botbooter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/slack-go/slack/slackevents"
+)
+
+func TestDiscordThreadName(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"first line", "deploy api\nwith details", "deploy api"},
+		{"empty", "   ", "Thread"},
+		{"truncated", strings.Repeat("a", 60), strings.Repeat("a", 50)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertEqual(t, discordThreadName(tt.content), tt.want, "Thread name")
+		})
+	}
+}
+
+func TestDiscordThreadID(t *testing.T) {
+	// Arrange
+	session := &discordgo.Session{State: discordgo.NewState()}
+	session.State.GuildAdd(&discordgo.Guild{ID: "guild123"})
+	session.State.ChannelAdd(&discordgo.Channel{ID: "thread123", GuildID: "guild123", Type: discordgo.ChannelTypeGuildPublicThread})
+	session.State.ChannelAdd(&discordgo.Channel{ID: "channel123", GuildID: "guild123", Type: discordgo.ChannelTypeGuildText})
+
+	// Act & Assert
+	assertEqual(t, discordThreadID(session, "thread123"), "thread123", "Thread channel")
+	assertEqual(t, discordThreadID(session, "channel123"), "", "Regular channel")
+	assertEqual(t, discordThreadID(session, "unknown"), "", "Unknown channel")
+}
+
+func TestBot_discordThreadFor(t *testing.T) {
+	t.Run("ExistingThread", func(t *testing.T) {
+		// Arrange
+		bot := InitAsDiscordBot("test_token")
+		message := &Message{ChannelID: "thread123", ThreadID: "thread123"}
+
+		// Act
+		threadID, err := bot.discordThreadFor(message)
+
+		// Assert
+		assertNoError(t, err, "Existing thread should be reused")
+		assertEqual(t, threadID, "thread123", "Thread ID")
+	})
+
+	t.Run("DirectMessage", func(t *testing.T) {
+		// Arrange
+		bot := InitAsDiscordBot("test_token")
+		message := &Message{
+			ChannelID: "dm123",
+			MessageID: "message123",
+			IsDirect:  true,
+		}
+
+		// Act
+		threadID, err := bot.discordThreadFor(message)
+
+		// Assert
+		assertNoError(t, err, "DMs should fall back to the channel")
+		assertEqual(t, threadID, "dm123", "DM channel ID")
+	})
+}
+
+func newDiscordThreadServer(t *testing.T, threadExists bool) (*Bot, *int, *[]string) {
+	threadStarts := 0
+	var postedTo []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/threads"):
+			threadStarts++
+			if threadExists || threadStarts > 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"code":160004,"message":"A thread has already been created for this message"}`))
+				return
+			}
+			w.Write([]byte(`{"id":"message123","type":11}`))
+		case strings.HasSuffix(r.URL.Path, "/messages"):
+			postedTo = append(postedTo, strings.Split(r.URL.Path, "/")[4])
+			w.Write([]byte(`{"id":"reply123"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	bot, err := NewDiscordBot(WithDiscordToken("test_token"), WithAPIURL(server.URL))
+	assertNoError(t, err, "NewDiscordBot should not fail")
+	return bot, &threadStarts, &postedTo
+}
+
+func TestBot_ReplyInThread_DiscordTwice(t *testing.T) {
+	// Arrange
+	bot, threadStarts, postedTo := newDiscordThreadServer(t, false)
+	message := &Message{ChannelID: "channel123", MessageID: "message123", Content: "deploy"}
+
+	// Act
+	firstErr := bot.ReplyInThread(message, "starting")
+	secondErr := bot.ReplyInThread(message, "done")
+
+	// Assert
+	assertNoError(t, firstErr, "First reply should start a thread")
+	assertNoError(t, secondErr, "Second reply should reuse the thread")
+	assertEqual(t, *threadStarts, 1, "Thread should only be started once")
+	assertEqual(t, len(*postedTo), 2, "Number of replies")
+	assertEqual(t, (*postedTo)[1], "message123", "Second reply goes to the thread")
+	assertEqual(t, message.ThreadID, "message123", "Thread ID is cached on the message")
+}
+
+func TestBot_ReplyInThread_DiscordThreadAlreadyExists(t *testing.T) {
+	// Arrange
+	bot, _, postedTo := newDiscordThreadServer(t, true)
+	message := &Message{ChannelID: "channel123", MessageID: "message123", Content: "deploy"}
+
+	// Act
+	err := bot.ReplyInThread(message, "done")
+
+	// Assert
+	assertNoError(t, err, "Existing thread should be reused")
+	assertEqual(t, (*postedTo)[0], "message123", "Reply goes to the message's thread")
+}
+
+func TestBot_ReplyInThread_UnknownBotType(t *testing.T) {
+	// Arrange
+	bot := &Bot{BotType: BotType(999)}
+
+	// Act
+	err := bot.ReplyInThread(&Message{ChannelID: "channel123", MessageID: "message123"}, "hi")
+
+	// Assert
+	assertError(t, err, "ReplyInThread with unknown bot type should fail")
+	assertEqual(t, err.Error(), "unknown bot type", "Error message for unknown bot type")
+}
+
+func TestHandleSlackEventsApi_ThreadFields(t *testing.T) {
+	// Arrange
+	bot := InitAsSlackBot("xapp-test", "xoxb-test")
+	var received *Message
+	bot.AddHandler(Command{
+		Pattern: "^hello$",
+		Handler: func(bot *Bot, message *Message) {
+			received = message
+		},
+	})
+	event := slackevents.EventsAPIEvent{
+		InnerEvent: slackevents.EventsAPIInnerEvent{
+			Data: &slackevents.MessageEvent{
+				Text:            "hello",
+				User:            "U123",
+				Channel:         "C456",
+				TimeStamp:       "1700000000.000200",
+				ThreadTimeStamp: "1700000000.000100",
+			},
+		},
+	}
+
+	// Act
+	bot.handleSlackEventsApi(event)
+
+	// Assert
+	assertNotNil(t, received, "Handler should be called")
+	assertEqual(t, received.MessageID, "1700000000.000200", "Message ID")
+	assertEqual(t, received.ThreadID, "1700000000.000100", "Thread ID")
+}